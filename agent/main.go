@@ -4,53 +4,65 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"sync"
+	"os"
 	"time"
 
 	"github.com/bnixon67/gomon"
 )
 
 func main() {
-	urls := []string{
-		"https://bn67.net",
-		"https://expired.badssl.com/",
-		"https://wrong.host.badssl.com/",
-		"http://example.com",
+	configs, err := loadConfigs()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	method := http.MethodGet
 
 	// Pre-allocate slice and populate directly
-	monitors := make([]*gomon.Monitor, len(urls))
-	for i, url := range urls {
+	monitors := make([]*gomon.Monitor, len(configs))
+	for i, config := range configs {
 		var err error
-		monitors[i], err = gomon.NewMonitor(
-			gomon.Config{
-				URL:            url,
-				Method:         method,
-				RequestTimeout: 10 * time.Second,
-				IgnoreCert:     true,
-				//DontFollowRedirect: true,
-			})
+		monitors[i], err = gomon.NewMonitor(config)
 		if err != nil {
 			fmt.Println(err)
 		}
 	}
 
-	var wg sync.WaitGroup
-	for _, m := range monitors {
-		if m != nil {
-			wg.Add(1)
-			go func(m *gomon.Monitor) {
-				defer wg.Done()
-				result, err := m.Check(context.Background())
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
-				fmt.Println(result)
-			}(m)
+	results, errs := gomon.CheckAll(context.Background(), monitors, 4)
+	for i, result := range results {
+		switch {
+		case errs[i] != nil:
+			fmt.Println(errs[i])
+		case result != nil:
+			fmt.Println(result)
+		}
+	}
+}
+
+// loadConfigs returns the monitors in the config file named on the command
+// line, or a built-in set if none is given.
+func loadConfigs() ([]gomon.Config, error) {
+	if len(os.Args) > 1 {
+		return gomon.LoadConfig(os.Args[1])
+	}
+
+	urls := []string{
+		"https://bn67.net",
+		"https://expired.badssl.com/",
+		"https://wrong.host.badssl.com/",
+		"http://example.com",
+	}
+	method := http.MethodGet
+
+	configs := make([]gomon.Config, len(urls))
+	for i, url := range urls {
+		configs[i] = gomon.Config{
+			URL:            url,
+			Method:         method,
+			RequestTimeout: 10 * time.Second,
+			IgnoreCert:     true,
+			//DontFollowRedirect: true,
 		}
 	}
 
-	wg.Wait()
+	return configs, nil
 }
@@ -0,0 +1,157 @@
+package gomon
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CheckAll checks each monitor using at most concurrency simultaneous
+// checks and returns the results and errors in the same order as monitors.
+// A concurrency less than one checks all monitors at once. Nil monitors are
+// skipped, leaving nil entries. Monitors not yet checked when ctx is done
+// report ctx.Err() in a *CheckError, with a result recording only the
+// error.
+func CheckAll(ctx context.Context, monitors []*Monitor, concurrency int) ([]*CheckResult, []error) {
+	results := make([]*CheckResult, len(monitors))
+	errs := make([]error, len(monitors))
+
+	if concurrency < 1 || concurrency > len(monitors) {
+		concurrency = len(monitors)
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = monitors[i].Check(ctx)
+			}
+		}()
+	}
+
+feed:
+	for i, m := range monitors {
+		if m == nil {
+			continue
+		}
+
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			now := time.Now()
+			for j := i; j < len(monitors); j++ {
+				if monitors[j] != nil {
+					config := monitors[j].config
+					result := &CheckResult{URL: config.URL, Method: config.Method, Start: now, End: now}
+					results[j], errs[j] = result.failed(&CheckError{Phase: PhaseRequest, URL: config.URL, Err: classifyContextErr(ctx, ctx.Err())})
+				}
+			}
+			break feed
+		}
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	return results, errs
+}
+
+// StreamResult is the outcome of checking Monitor, delivered by
+// CheckStream.
+type StreamResult struct {
+	Monitor *Monitor
+	Result  *CheckResult
+	Err     error
+}
+
+// CheckStream checks each monitor from monitors using at most concurrency
+// simultaneous checks, and delivers the outcomes on the returned channel
+// as they complete, for fleets too large to collect with CheckAll. Use
+// slices.Values for a slice of monitors. A concurrency less than one is
+// treated as one, and nil monitors are skipped.
+//
+// Nothing is buffered: while the caller is not receiving, the checks in
+// progress wait to deliver their results and no more monitors are taken
+// from the sequence. The caller should receive until the channel is
+// closed, or cancel ctx. Once ctx is done, no more monitors are taken, the
+// checks in progress stop, and their outcomes may be dropped; the channel
+// is closed when they have returned.
+func CheckStream(ctx context.Context, monitors iter.Seq[*Monitor], concurrency int) <-chan StreamResult {
+	pending := make(chan *Monitor)
+	results := make(chan StreamResult)
+
+	go func() {
+		defer close(pending)
+		for m := range monitors {
+			if m == nil {
+				continue
+			}
+
+			select {
+			case pending <- m:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range pending {
+				result, err := m.Check(ctx)
+
+				select {
+				case results <- StreamResult{Monitor: m, Result: result, Err: err}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// SortByExpiry returns the results with certificate information, sorted by
+// certificate expiry with the soonest first. Results without CertInfo are
+// omitted. The results slice is not modified.
+func SortByExpiry(results []*CheckResult) []*CheckResult {
+	var sorted []*CheckResult
+	for _, result := range results {
+		if result != nil && result.CertInfo != nil {
+			sorted = append(sorted, result)
+		}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b *CheckResult) int {
+		return a.CertInfo.ValidTo.Compare(b.CertInfo.ValidTo)
+	})
+
+	return sorted
+}
+
+// ExpiringWithin returns the results whose certificate expires within d,
+// including those already expired, in their original order.
+func ExpiringWithin(results []*CheckResult, d time.Duration) []*CheckResult {
+	var expiring []*CheckResult
+	for _, result := range results {
+		if result != nil && result.CertInfo != nil && result.CertInfo.ExpiresWithin(d) {
+			expiring = append(expiring, result)
+		}
+	}
+
+	return expiring
+}
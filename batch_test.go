@@ -0,0 +1,203 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckAll(t *testing.T) {
+	var active, peak atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		code, _ := strconv.Atoi(r.URL.Path[1:])
+		w.WriteHeader(code)
+	}))
+	defer ts.Close()
+
+	codes := []int{200, 503, 201, 404, 200, 500}
+	monitors := make([]*Monitor, len(codes)+1)
+	for i, code := range codes {
+		m, err := NewMonitor(Config{URL: ts.URL + "/" + strconv.Itoa(code), Method: http.MethodGet})
+		if err != nil {
+			t.Fatalf("NewMonitor() error = %v", err)
+		}
+		monitors[i] = m
+	}
+
+	const concurrency = 2
+	results, errs := CheckAll(context.Background(), monitors, concurrency)
+
+	for i, code := range codes {
+		if errs[i] != nil {
+			t.Fatalf("errs[%d] = %v", i, errs[i])
+		}
+		if results[i].StatusCode != code {
+			t.Errorf("results[%d].StatusCode = %d, want %d", i, results[i].StatusCode, code)
+		}
+	}
+	if last := len(monitors) - 1; results[last] != nil || errs[last] != nil {
+		t.Errorf("nil monitor was not skipped")
+	}
+	if got := peak.Load(); got > concurrency {
+		t.Errorf("peak concurrency = %d, want <= %d", got, concurrency)
+	}
+}
+
+func TestCheckAllCanceled(t *testing.T) {
+	m, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, errs := CheckAll(ctx, []*Monitor{m, m, m}, 1)
+	for i, err := range errs {
+		var checkErr *CheckError
+		if !errors.Is(err, context.Canceled) || !errors.As(err, &checkErr) {
+			t.Errorf("errs[%d] = %v, want *CheckError for context.Canceled", i, err)
+		}
+		if results[i] == nil || results[i].IsUp() || results[i].Error == "" {
+			t.Errorf("results[%d] = %+v, want down result with Error", i, results[i])
+		}
+	}
+}
+
+func TestCheckStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(r.URL.Path[1:])
+		w.WriteHeader(code)
+	}))
+	defer ts.Close()
+
+	codes := []int{200, 503, 201, 404, 200, 500, 204, 302}
+	monitors := []*Monitor{nil}
+	for _, code := range codes {
+		m, err := NewMonitor(Config{URL: ts.URL + "/" + strconv.Itoa(code), Method: http.MethodGet, DontFollowRedirect: true})
+		if err != nil {
+			t.Fatalf("NewMonitor() error = %v", err)
+		}
+		monitors = append(monitors, m)
+	}
+
+	var taken atomic.Int32
+	seq := func(yield func(*Monitor) bool) {
+		for _, m := range monitors {
+			taken.Add(1)
+			if !yield(m) {
+				return
+			}
+		}
+	}
+
+	const concurrency = 2
+	results := CheckStream(context.Background(), seq, concurrency)
+
+	// Without a receiver, each worker holds one result and the producer
+	// one monitor; the nil monitor is skipped without blocking.
+	time.Sleep(100 * time.Millisecond)
+	if got, limit := taken.Load(), int32(concurrency+2); got > limit {
+		t.Errorf("monitors taken without a receiver = %d, want <= %d", got, limit)
+	}
+
+	var got []int
+	for r := range results {
+		if r.Err != nil {
+			t.Fatalf("Err = %v", r.Err)
+		}
+		if want := r.Monitor.config.URL; r.Result.URL != want {
+			t.Errorf("Result.URL = %q, want %q", r.Result.URL, want)
+		}
+		got = append(got, r.Result.StatusCode)
+	}
+
+	slices.Sort(got)
+	want := slices.Sorted(slices.Values(codes))
+	if !slices.Equal(got, want) {
+		t.Errorf("status codes = %v, want %v", got, want)
+	}
+}
+
+func TestCheckStreamCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	endless := func(yield func(*Monitor) bool) {
+		for yield(m) {
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := CheckStream(ctx, endless, 4)
+	<-results
+	cancel()
+
+	// Once canceled, the stream ends without the caller receiving the
+	// outcomes of the checks in progress.
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("results channel not closed after cancel")
+		}
+	}
+}
+
+func TestSortByExpiry(t *testing.T) {
+	now := time.Now()
+	expiresIn := func(url string, d time.Duration) *CheckResult {
+		return &CheckResult{URL: url, CertInfo: &CertInfo{ValidTo: now.Add(d)}}
+	}
+
+	results := []*CheckResult{
+		expiresIn("late", 90*24*time.Hour),
+		{URL: "plain http"},
+		nil,
+		expiresIn("expired", -24*time.Hour),
+		expiresIn("soon", 5*24*time.Hour),
+	}
+
+	urls := func(results []*CheckResult) []string {
+		var urls []string
+		for _, result := range results {
+			urls = append(urls, result.URL)
+		}
+		return urls
+	}
+
+	if got, want := urls(SortByExpiry(results)), []string{"expired", "soon", "late"}; !slices.Equal(got, want) {
+		t.Errorf("SortByExpiry() = %v, want %v", got, want)
+	}
+	if results[0].URL != "late" {
+		t.Errorf("SortByExpiry() modified its argument")
+	}
+
+	if got, want := urls(ExpiringWithin(results, 30*24*time.Hour)), []string{"expired", "soon"}; !slices.Equal(got, want) {
+		t.Errorf("ExpiringWithin() = %v, want %v", got, want)
+	}
+}
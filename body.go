@@ -0,0 +1,76 @@
+package gomon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+)
+
+// newRequest creates the request for an attempt, with the configured body.
+func (m *Monitor) newRequest(ctx context.Context) (*http.Request, error) {
+	body, size, err := m.openBody()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, m.config.Method, m.config.URL, nil)
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil, err
+	}
+
+	if body == nil {
+		return req, nil
+	}
+
+	if size == 0 {
+		body.Close()
+		req.Body = http.NoBody
+	} else {
+		req.Body = body
+		req.ContentLength = size
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		body, _, err := m.openBody()
+		return body, err
+	}
+
+	return req, nil
+}
+
+// openBody opens the configured request body and returns it with its size,
+// or -1 if the size is unknown. It returns a nil body if none is
+// configured.
+func (m *Monitor) openBody() (io.ReadCloser, int64, error) {
+	var (
+		body io.ReadCloser
+		err  error
+	)
+
+	switch {
+	case m.config.RequestBodyFile != "":
+		body, err = os.Open(m.config.RequestBodyFile)
+	case m.config.RequestBodyFunc != nil:
+		body, err = m.config.RequestBodyFunc()
+	default:
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open request body: %w", err)
+	}
+
+	size := int64(-1)
+	if statter, ok := body.(interface{ Stat() (fs.FileInfo, error) }); ok {
+		if info, err := statter.Stat(); err == nil && info.Mode().IsRegular() {
+			size = info.Size()
+		}
+	}
+
+	return body, size, nil
+}
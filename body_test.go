@@ -0,0 +1,153 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// closeRecorder records whether the body was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMonitor_CheckRequestBody(t *testing.T) {
+	const payload = "a large payload"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "length=%d body=%s", r.ContentLength, body)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/echo", http.StatusTemporaryRedirect)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "body.txt")
+	if err := os.WriteFile(file, []byte(payload), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var bodies []*closeRecorder
+	bodyFunc := func() (io.ReadCloser, error) {
+		body := &closeRecorder{Reader: strings.NewReader(payload)}
+		bodies = append(bodies, body)
+		return body, nil
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		bodyFile  string
+		bodyFunc  func() (io.ReadCloser, error)
+		wantBody  string
+		wantOpens int
+	}{
+		{name: "File", path: "/echo", bodyFile: file, wantBody: fmt.Sprintf("length=%d body=%s", len(payload), payload)},
+		{name: "File redirected", path: "/redirect", bodyFile: file, wantBody: fmt.Sprintf("length=%d body=%s", len(payload), payload)},
+		{name: "Func", path: "/echo", bodyFunc: bodyFunc, wantBody: "length=-1 body=" + payload, wantOpens: 1},
+		{name: "Func redirected", path: "/redirect", bodyFunc: bodyFunc, wantBody: "length=-1 body=" + payload, wantOpens: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies = nil
+
+			m, err := NewMonitor(Config{
+				URL:             ts.URL + tt.path,
+				Method:          http.MethodPost,
+				RequestBodyFile: tt.bodyFile,
+				RequestBodyFunc: tt.bodyFunc,
+				BodyContains:    tt.wantBody,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.BodyMatched {
+				t.Errorf("server did not receive %q", tt.wantBody)
+			}
+			if len(bodies) != tt.wantOpens {
+				t.Errorf("body opened %d times, want %d", len(bodies), tt.wantOpens)
+			}
+			for i, body := range bodies {
+				if !body.closed {
+					t.Errorf("body %d not closed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRequestBodyErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	errOpen := errors.New("open failed")
+
+	tests := []struct {
+		name     string
+		bodyFile string
+		bodyFunc func() (io.ReadCloser, error)
+		wantErr  error
+	}{
+		{name: "Missing file", bodyFile: filepath.Join(t.TempDir(), "missing"), wantErr: os.ErrNotExist},
+		{name: "Func error", bodyFunc: func() (io.ReadCloser, error) { return nil, errOpen }, wantErr: errOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:             ts.URL,
+				Method:          http.MethodPost,
+				RequestBodyFile: tt.bodyFile,
+				RequestBodyFunc: tt.bodyFunc,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			var checkErr *CheckError
+			if !errors.As(err, &checkErr) || checkErr.Phase != PhaseRequest {
+				t.Fatalf("Check() error = %v, want request error", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if got == nil || got.Err != err || got.URL != ts.URL || got.Start.IsZero() {
+				t.Errorf("Check() result = %+v, want URL, Start and Err set", got)
+			}
+		})
+	}
+
+	_, err := NewMonitor(Config{
+		URL:             ts.URL,
+		Method:          http.MethodPost,
+		RequestBodyFile: "body.txt",
+		RequestBodyFunc: func() (io.ReadCloser, error) { return nil, nil },
+	})
+	if err == nil {
+		t.Error("NewMonitor() with both bodies error = nil, want error")
+	}
+}
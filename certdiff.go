@@ -0,0 +1,80 @@
+package gomon
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CertChange describes a field that differs between two CertInfo
+// snapshots.
+type CertChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// String returns a description of the change.
+func (cc CertChange) String() string {
+	return fmt.Sprintf("%s: %q -> %q", cc.Field, cc.Old, cc.New)
+}
+
+// Diff reports the identifying fields of the certificate that differ in
+// other, such as after a rotation: Subject, Issuer, ValidFrom, ValidTo,
+// DNSNames and Fingerprint. DNS names are compared regardless of order.
+// The validity and connection details, such as IsValid and TLSVersion, are
+// not compared, as they can change without a new certificate. A nil
+// CertInfo is treated as having every field empty.
+func (c *CertInfo) Diff(other *CertInfo) []CertChange {
+	var changes []CertChange
+
+	compare := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, CertChange{Field: field, Old: before, New: after})
+		}
+	}
+
+	a, b := c.identity(), other.identity()
+	compare("Subject", a.Subject, b.Subject)
+	compare("Issuer", a.Issuer, b.Issuer)
+	compare("ValidFrom", formatCertTime(a.ValidFrom), formatCertTime(b.ValidFrom))
+	compare("ValidTo", formatCertTime(a.ValidTo), formatCertTime(b.ValidTo))
+	compare("DNSNames", strings.Join(a.DNSNames, ", "), strings.Join(b.DNSNames, ", "))
+	compare("Fingerprint", a.Fingerprint, b.Fingerprint)
+
+	return changes
+}
+
+// Equal reports whether other describes the same certificate, comparing
+// the fields that Diff does.
+func (c *CertInfo) Equal(other *CertInfo) bool {
+	return len(c.Diff(other)) == 0
+}
+
+// identity returns a copy of the fields of c compared by Diff, with sorted
+// DNS names, or a zero CertInfo if c is nil.
+func (c *CertInfo) identity() CertInfo {
+	if c == nil {
+		return CertInfo{}
+	}
+
+	return CertInfo{
+		Subject:     c.Subject,
+		Issuer:      c.Issuer,
+		ValidFrom:   c.ValidFrom,
+		ValidTo:     c.ValidTo,
+		DNSNames:    slices.Sorted(slices.Values(c.DNSNames)),
+		Fingerprint: c.Fingerprint,
+	}
+}
+
+// formatCertTime formats t in UTC, so that the same instant compares equal
+// whatever its location, or returns "" for the zero time.
+func formatCertTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,87 @@
+package gomon
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCertInfoDiff(t *testing.T) {
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &CertInfo{
+		Subject:     "CN=example.com",
+		Issuer:      "CN=R11,O=Let's Encrypt,C=US",
+		ValidFrom:   validFrom,
+		ValidTo:     validFrom.Add(90 * 24 * time.Hour),
+		DNSNames:    []string{"example.com", "www.example.com"},
+		IsValid:     true,
+		Fingerprint: "aa",
+		TLSVersion:  "TLS 1.3",
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *CertInfo)
+		want   []string
+	}{
+		{name: "Unchanged", change: func(c *CertInfo) {}},
+		{
+			name: "Same details, different form",
+			change: func(c *CertInfo) {
+				c.ValidFrom = c.ValidFrom.In(time.FixedZone("EST", -5*60*60))
+				c.DNSNames = []string{"www.example.com", "example.com"}
+				c.IsValid = false
+				c.TLSVersion = "TLS 1.2"
+			},
+		},
+		{
+			name: "Rotated",
+			change: func(c *CertInfo) {
+				c.ValidFrom = c.ValidFrom.Add(60 * 24 * time.Hour)
+				c.ValidTo = c.ValidTo.Add(60 * 24 * time.Hour)
+				c.Fingerprint = "bb"
+			},
+			want: []string{"ValidFrom", "ValidTo", "Fingerprint"},
+		},
+		{
+			name: "New issuer and names",
+			change: func(c *CertInfo) {
+				c.Issuer = "CN=Other CA"
+				c.DNSNames = []string{"example.com"}
+			},
+			want: []string{"Issuer", "DNSNames"},
+		},
+		{
+			name: "Nil",
+			want: []string{"Subject", "Issuer", "ValidFrom", "ValidTo", "DNSNames", "Fingerprint"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var other *CertInfo
+			if tt.change != nil {
+				c := *base
+				tt.change(&c)
+				other = &c
+			}
+
+			changes := base.Diff(other)
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.Field)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %v, want fields %v", changes, tt.want)
+			}
+			if eq := base.Equal(other); eq != (len(tt.want) == 0) {
+				t.Errorf("Equal() = %v, want %v", eq, len(tt.want) == 0)
+			}
+		})
+	}
+
+	var none *CertInfo
+	if !none.Equal(nil) {
+		t.Errorf("nil Equal(nil) = false, want true")
+	}
+}
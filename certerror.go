@@ -0,0 +1,69 @@
+package gomon
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+)
+
+// CertError classifies why a certificate is invalid.
+type CertError string
+
+const (
+	CertErrorNone             CertError = ""
+	CertErrorExpired          CertError = "expired"
+	CertErrorNotYetValid      CertError = "not_yet_valid"
+	CertErrorHostname         CertError = "hostname_mismatch" // the name, usually from SNI, is not in the certificate
+	CertErrorUnknownAuthority CertError = "unknown_authority"
+	CertErrorRevoked          CertError = "revoked"
+	CertErrorOther            CertError = "other"
+)
+
+// ClassifyCertError returns the CertError describing err, which may be a
+// verification error from the x509 package or an error returned by Check
+// for a failed TLS handshake. It returns CertErrorNone if err is not a
+// certificate error.
+func ClassifyCertError(err error) CertError {
+	var (
+		hostnameErr  x509.HostnameError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	switch {
+	case err == nil:
+		return CertErrorNone
+	case errors.As(err, &hostnameErr):
+		return CertErrorHostname
+	case errors.As(err, &authorityErr):
+		return CertErrorUnknownAuthority
+	case errors.As(err, &invalidErr):
+		if invalidErr.Reason == x509.Expired {
+			return CertErrorExpired
+		}
+		return CertErrorOther
+	case isTLSError(err):
+		return CertErrorOther
+	default:
+		return CertErrorNone
+	}
+}
+
+// certErrorMessage describes a verification error of the given class.
+func certErrorMessage(code CertError, err error) string {
+	switch code {
+	case CertErrorHostname:
+		return "SNI/hostname mismatch: " + err.Error()
+	case CertErrorUnknownAuthority:
+		return "untrusted certificate chain: " + err.Error()
+	default:
+		return "certificate verification failed: " + err.Error()
+	}
+}
+
+// isSelfSigned reports whether cert names itself as issuer and is signed by
+// its own key.
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
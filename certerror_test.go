@@ -0,0 +1,141 @@
+package gomon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckCertErrorCode(t *testing.T) {
+	// The test certificate is issued for example.com and 127.0.0.1, but
+	// not localhost.
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	localhostURL := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	tests := []struct {
+		name       string
+		url        string
+		rootCAs    *x509.CertPool
+		wantCode   CertError
+		wantPrefix string
+	}{
+		{name: "Valid", url: ts.URL, rootCAs: pool, wantCode: CertErrorNone},
+		{name: "Hostname mismatch", url: localhostURL, rootCAs: pool, wantCode: CertErrorHostname, wantPrefix: "SNI/hostname mismatch"},
+		{name: "Untrusted self-signed", url: ts.URL, wantCode: CertErrorUnknownAuthority, wantPrefix: "self-signed certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:        tt.url,
+				Method:     http.MethodGet,
+				IgnoreCert: true, // report the problem instead of failing the handshake
+				RootCAs:    tt.rootCAs,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.CertInfo.ErrorCode != tt.wantCode {
+				t.Errorf("ErrorCode = %q, want %q (ErrorMsg %q)", got.CertInfo.ErrorCode, tt.wantCode, got.CertInfo.ErrorMsg)
+			}
+			if !strings.HasPrefix(got.CertInfo.ErrorMsg, tt.wantPrefix) {
+				t.Errorf("ErrorMsg = %q, want prefix %q", got.CertInfo.ErrorMsg, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestClassifyCertError(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	m, err := NewMonitor(Config{
+		URL:          ts.URL,
+		Method:       http.MethodGet,
+		RootCAs:      pool,
+		HostOverride: "vhost.test",
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	_, err = m.Check(context.Background())
+	if got := ClassifyCertError(err); got != CertErrorHostname {
+		t.Errorf("ClassifyCertError(%v) = %q, want %q", err, got, CertErrorHostname)
+	}
+
+	if got := ClassifyCertError(errors.New("connection refused")); got != CertErrorNone {
+		t.Errorf("ClassifyCertError() = %q, want %q", got, CertErrorNone)
+	}
+}
+
+func TestCertInfo_SelfSigned(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leaf, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf.example.com"},
+		DNSNames:     []string{"leaf.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, ca, caKey)
+	selfSigned, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "self.example.com"},
+		DNSNames:     []string{"self.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, nil, nil)
+	// Issued in the name of the CA, but not signed by its key.
+	impostor, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "Test CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, ca, caKey)
+
+	tests := []struct {
+		name       string
+		cert       *x509.Certificate
+		host       string
+		wantSigned bool
+		wantPrefix string
+	}{
+		{name: "Self-signed leaf", cert: selfSigned, host: "self.example.com", wantSigned: true, wantPrefix: "self-signed certificate"},
+		{name: "Self-signed CA", cert: ca, wantSigned: true, wantPrefix: "self-signed certificate"},
+		{name: "CA-issued leaf", cert: leaf, host: "leaf.example.com", wantPrefix: "untrusted certificate chain"},
+		{name: "Self-issued, not self-signed", cert: impostor, wantPrefix: "untrusted certificate chain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := certInfo(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}, tt.host, x509.NewCertPool())
+
+			if info.SelfSigned != tt.wantSigned {
+				t.Errorf("SelfSigned = %v, want %v", info.SelfSigned, tt.wantSigned)
+			}
+			if !strings.HasPrefix(info.ErrorMsg, tt.wantPrefix) {
+				t.Errorf("ErrorMsg = %q, want prefix %q", info.ErrorMsg, tt.wantPrefix)
+			}
+		})
+	}
+}
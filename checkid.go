@@ -0,0 +1,21 @@
+package gomon
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// CheckIDHeader is the request header carrying the check ID when
+// Config.SendCheckID is set.
+const CheckIDHeader = "X-Gomon-Check-Id"
+
+// newCheckID returns a random version 4 UUID.
+func newCheckID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
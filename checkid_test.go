@@ -0,0 +1,83 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckID(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		ids = append(ids, r.Header.Get(CheckIDHeader))
+		if len(ids) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	tests := []struct {
+		name string
+		send bool
+	}{
+		{name: "Sent", send: true},
+		{name: "Not sent", send: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids = nil
+
+			m, err := NewMonitor(Config{
+				URL:             ts.URL,
+				Method:          http.MethodGet,
+				RetryCount:      1,
+				RetryDelay:      time.Millisecond,
+				RetryDownStatus: true,
+				SendCheckID:     tt.send,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			first, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !uuid.MatchString(first.CheckID) {
+				t.Errorf("CheckID = %q, want a version 4 UUID", first.CheckID)
+			}
+
+			want := ""
+			if tt.send {
+				want = first.CheckID
+			}
+			mu.Lock()
+			for i, id := range ids {
+				if id != want {
+					t.Errorf("attempt %d sent %s %q, want %q", i+1, CheckIDHeader, id, want)
+				}
+			}
+			mu.Unlock()
+
+			second, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if second.CheckID == first.CheckID {
+				t.Errorf("two checks share CheckID %q", first.CheckID)
+			}
+		})
+	}
+}
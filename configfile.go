@@ -0,0 +1,327 @@
+package gomon
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"time"
+)
+
+// fileConfig is the JSON form of a Config in a configuration file. Fields
+// that cannot be expressed in JSON, such as HTTPClient, are omitted.
+type fileConfig struct {
+	URL                      string      `json:"url"`
+	Method                   string      `json:"method"`
+	RequestTimeout           duration    `json:"request_timeout"`
+	IgnoreCert               bool        `json:"ignore_cert"`
+	DontFollowRedirect       bool        `json:"dont_follow_redirect"`
+	UpStatusCodes            []int       `json:"up_status_codes"`
+	UpStatusRanges           []string    `json:"up_status_ranges"`
+	RequestBodyFile          string      `json:"request_body_file"`
+	Headers                  http.Header `json:"headers"`
+	RetryCount               int         `json:"retry_count"`
+	RetryDelay               duration    `json:"retry_delay"`
+	RetryBackoff             float64     `json:"retry_backoff"`
+	RetryDownStatus          bool        `json:"retry_down_status"`
+	RetryPolicy              *filePolicy `json:"retry_policy"`
+	AllowUnsafeRetries       bool        `json:"allow_unsafe_retries"`
+	BasicAuthUser            string      `json:"basic_auth_user"`
+	BasicAuthPass            string      `json:"basic_auth_pass"`
+	BodyContains             string      `json:"body_contains"`
+	MaxBodyBytes             int64       `json:"max_body_bytes"`
+	UserAgent                string      `json:"user_agent"`
+	Accept                   string      `json:"accept"`
+	AcceptEncoding           string      `json:"accept_encoding"`
+	AcceptLanguage           string      `json:"accept_language"`
+	DisableCacheBusting      bool        `json:"disable_cache_busting"`
+	CacheBustParam           string      `json:"cache_bust_param"`
+	ExpectedHeaders          http.Header `json:"expected_headers"`
+	ExpectedTrailers         http.Header `json:"expected_trailers"`
+	ResponseTimeThreshold    duration    `json:"response_time_threshold"`
+	MaxClockSkew             duration    `json:"max_clock_skew"`
+	TotalTimeout             duration    `json:"total_timeout"`
+	MinTLSVersion            tlsVersion  `json:"min_tls_version"`
+	AllowCustomMethod        bool        `json:"allow_custom_method"`
+	Proxy                    string      `json:"proxy"`
+	DialIP                   string      `json:"dial_ip"`
+	SocketPath               string      `json:"socket_path"`
+	CheckRevocation          bool        `json:"check_revocation"`
+	ConfirmCount             int         `json:"confirm_count"`
+	ClientCertFile           string      `json:"client_cert_file"`
+	ClientKeyFile            string      `json:"client_key_file"`
+	RootCAFile               string      `json:"root_ca_file"`
+	MaxRedirects             int         `json:"max_redirects"`
+	Network                  string      `json:"network"`
+	ReadBody                 bool        `json:"read_body"`
+	DisableKeepAlives        bool        `json:"disable_keep_alives"`
+	CaptureHeaders           []string    `json:"capture_headers"`
+	SecurityHeaders          []string    `json:"security_headers"`
+	CaptureBodyOnFailure     bool        `json:"capture_body_on_failure"`
+	BodySnippetBytes         int64       `json:"body_snippet_bytes"`
+	AcceptRedirects          bool        `json:"accept_redirects"`
+	InvertResult             bool        `json:"invert_result"`
+	ExpectedRedirectLocation string      `json:"expected_redirect_location"`
+	ExpectedCertFingerprint  string      `json:"expected_cert_fingerprint"`
+	ExpectedIssuers          []string    `json:"expected_issuers"`
+	Jitter                   duration    `json:"jitter"`
+	DNSTimeout               duration    `json:"dns_timeout"`
+	DNSServer                string      `json:"dns_server"`
+	DialTimeout              duration    `json:"dial_timeout"`
+	TLSHandshakeTimeout      duration    `json:"tls_handshake_timeout"`
+	MaxIdleConns             int         `json:"max_idle_conns"`
+	MaxIdleConnsPerHost      int         `json:"max_idle_conns_per_host"`
+	IdleConnTimeout          duration    `json:"idle_conn_timeout"`
+	CertVerifyHost           string      `json:"cert_verify_host"`
+	MinBodyBytes             int64       `json:"min_body_bytes"`
+	MinThroughput            int64       `json:"min_throughput"`
+	ExpectHTTP2              bool        `json:"expect_http2"`
+	HostOverride             string      `json:"host_override"`
+	SendCheckID              bool        `json:"send_check_id"`
+}
+
+// config converts fc to a Config.
+func (fc fileConfig) config() Config {
+	return Config{
+		URL:                      fc.URL,
+		Method:                   fc.Method,
+		RequestTimeout:           time.Duration(fc.RequestTimeout),
+		IgnoreCert:               fc.IgnoreCert,
+		DontFollowRedirect:       fc.DontFollowRedirect,
+		UpStatusCodes:            fc.UpStatusCodes,
+		UpStatusRanges:           fc.UpStatusRanges,
+		RequestBodyFile:          fc.RequestBodyFile,
+		Headers:                  fc.Headers,
+		RetryCount:               fc.RetryCount,
+		RetryDelay:               time.Duration(fc.RetryDelay),
+		RetryBackoff:             fc.RetryBackoff,
+		RetryDownStatus:          fc.RetryDownStatus,
+		RetryPolicy:              fc.RetryPolicy.policy(),
+		AllowUnsafeRetries:       fc.AllowUnsafeRetries,
+		BasicAuthUser:            fc.BasicAuthUser,
+		BasicAuthPass:            fc.BasicAuthPass,
+		BodyContains:             fc.BodyContains,
+		MaxBodyBytes:             fc.MaxBodyBytes,
+		UserAgent:                fc.UserAgent,
+		Accept:                   fc.Accept,
+		AcceptEncoding:           fc.AcceptEncoding,
+		AcceptLanguage:           fc.AcceptLanguage,
+		DisableCacheBusting:      fc.DisableCacheBusting,
+		CacheBustParam:           fc.CacheBustParam,
+		ExpectedHeaders:          fc.ExpectedHeaders,
+		ExpectedTrailers:         fc.ExpectedTrailers,
+		ResponseTimeThreshold:    time.Duration(fc.ResponseTimeThreshold),
+		MaxClockSkew:             time.Duration(fc.MaxClockSkew),
+		TotalTimeout:             time.Duration(fc.TotalTimeout),
+		MinTLSVersion:            uint16(fc.MinTLSVersion),
+		AllowCustomMethod:        fc.AllowCustomMethod,
+		Proxy:                    fc.Proxy,
+		DialIP:                   fc.DialIP,
+		SocketPath:               fc.SocketPath,
+		CheckRevocation:          fc.CheckRevocation,
+		ConfirmCount:             fc.ConfirmCount,
+		ClientCertFile:           fc.ClientCertFile,
+		ClientKeyFile:            fc.ClientKeyFile,
+		RootCAFile:               fc.RootCAFile,
+		MaxRedirects:             fc.MaxRedirects,
+		Network:                  fc.Network,
+		ReadBody:                 fc.ReadBody,
+		DisableKeepAlives:        fc.DisableKeepAlives,
+		CaptureHeaders:           fc.CaptureHeaders,
+		SecurityHeaders:          fc.SecurityHeaders,
+		CaptureBodyOnFailure:     fc.CaptureBodyOnFailure,
+		BodySnippetBytes:         fc.BodySnippetBytes,
+		AcceptRedirects:          fc.AcceptRedirects,
+		InvertResult:             fc.InvertResult,
+		ExpectedRedirectLocation: fc.ExpectedRedirectLocation,
+		ExpectedCertFingerprint:  fc.ExpectedCertFingerprint,
+		ExpectedIssuers:          fc.ExpectedIssuers,
+		Jitter:                   time.Duration(fc.Jitter),
+		DNSTimeout:               time.Duration(fc.DNSTimeout),
+		DNSServer:                fc.DNSServer,
+		DialTimeout:              time.Duration(fc.DialTimeout),
+		TLSHandshakeTimeout:      time.Duration(fc.TLSHandshakeTimeout),
+		MaxIdleConns:             fc.MaxIdleConns,
+		MaxIdleConnsPerHost:      fc.MaxIdleConnsPerHost,
+		IdleConnTimeout:          time.Duration(fc.IdleConnTimeout),
+		CertVerifyHost:           fc.CertVerifyHost,
+		MinBodyBytes:             fc.MinBodyBytes,
+		MinThroughput:            fc.MinThroughput,
+		ExpectHTTP2:              fc.ExpectHTTP2,
+		HostOverride:             fc.HostOverride,
+		SendCheckID:              fc.SendCheckID,
+	}
+}
+
+// filePolicy is the JSON form of a RetryPolicy. Its retry_down field
+// selects RetryOnDown in place of RetryOnError.
+type filePolicy struct {
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelay   duration `json:"base_delay"`
+	MaxDelay    duration `json:"max_delay"`
+	Multiplier  float64  `json:"multiplier"`
+	Jitter      float64  `json:"jitter"`
+	RetryDown   bool     `json:"retry_down"`
+}
+
+// policy converts fp to a RetryPolicy, or nil if fp is nil.
+func (fp *filePolicy) policy() *RetryPolicy {
+	if fp == nil {
+		return nil
+	}
+
+	policy := &RetryPolicy{
+		MaxAttempts: fp.MaxAttempts,
+		BaseDelay:   time.Duration(fp.BaseDelay),
+		MaxDelay:    time.Duration(fp.MaxDelay),
+		Multiplier:  fp.Multiplier,
+		Jitter:      fp.Jitter,
+	}
+	if fp.RetryDown {
+		policy.Retryable = RetryOnDown
+	}
+
+	return policy
+}
+
+// duration is a time.Duration written in JSON as a string, such as "10s".
+type duration time.Duration
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"10s\": %s", data)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+// tlsVersion is a TLS version written in JSON as a string, such as "1.2".
+type tlsVersion uint16
+
+// tlsVersions maps the JSON names of TLS versions to their values.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *tlsVersion) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("TLS version must be a string such as \"1.2\": %s", data)
+	}
+
+	version, ok := tlsVersions[s]
+	if !ok {
+		return fmt.Errorf("unknown TLS version %q", s)
+	}
+
+	*v = tlsVersion(version)
+	return nil
+}
+
+// configFile is the layout of a configuration file. The fields in Defaults
+// apply to every monitor that does not set them itself.
+type configFile struct {
+	Defaults map[string]json.RawMessage   `json:"defaults"`
+	Monitors []map[string]json.RawMessage `json:"monitors"`
+}
+
+// ParseConfig reads monitor configurations in JSON from r. The input is an
+// object with a "monitors" array and an optional "defaults" object, whose
+// fields apply to each monitor that does not set them:
+//
+//	{
+//	  "defaults": {"method": "GET", "request_timeout": "10s"},
+//	  "monitors": [
+//	    {"url": "https://example.com", "body_contains": "Example"},
+//	    {"url": "https://example.org", "retry_count": 2}
+//	  ]
+//	}
+//
+// Field names are the snake_case forms of the Config fields. Durations are
+// strings such as "500ms" or "1m30s", and min_tls_version is a string such
+// as "1.2". Unknown fields are an error, and each configuration is
+// validated as by NewMonitor.
+func ParseConfig(r io.Reader) ([]Config, error) {
+	var file configFile
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var (
+		configs []Config
+		errs    []error
+	)
+	for i, fields := range file.Monitors {
+		merged := maps.Clone(file.Defaults)
+		if merged == nil {
+			merged = make(map[string]json.RawMessage)
+		}
+		maps.Copy(merged, fields)
+
+		config, err := decodeConfig(merged)
+		if err == nil {
+			_, err = NewMonitor(config)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("monitor %d: %w", i, err))
+			continue
+		}
+
+		configs = append(configs, config)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return configs, nil
+}
+
+// LoadConfig reads monitor configurations from the named file, as
+// described by ParseConfig.
+func LoadConfig(name string) ([]Config, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseConfig(f)
+}
+
+// decodeConfig decodes the JSON fields of one monitor into a Config.
+func decodeConfig(fields map[string]json.RawMessage) (Config, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var fc fileConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fc); err != nil {
+		return Config{}, err
+	}
+
+	return fc.config(), nil
+}
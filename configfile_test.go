@@ -0,0 +1,136 @@
+package gomon
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Config
+		wantErr string
+	}{
+		{
+			name: "Defaults applied",
+			input: `{
+				"defaults": {"method": "GET", "request_timeout": "5s", "retry_count": 1},
+				"monitors": [
+					{"url": "https://example.com", "body_contains": "Example", "min_tls_version": "1.2"},
+					{"url": "https://example.org", "retry_count": 3, "retry_delay": "250ms",
+					 "headers": {"Accept": ["application/json"]}}
+				]
+			}`,
+			want: []Config{
+				{
+					URL:            "https://example.com",
+					Method:         http.MethodGet,
+					RequestTimeout: 5 * time.Second,
+					RetryCount:     1,
+					BodyContains:   "Example",
+					MinTLSVersion:  tls.VersionTLS12,
+				},
+				{
+					URL:            "https://example.org",
+					Method:         http.MethodGet,
+					RequestTimeout: 5 * time.Second,
+					RetryCount:     3,
+					RetryDelay:     250 * time.Millisecond,
+					Headers:        http.Header{"Accept": {"application/json"}},
+				},
+			},
+		},
+		{
+			name: "Retry policy",
+			input: `{"monitors": [{"url": "https://example.com", "method": "GET",
+				"retry_policy": {"max_attempts": 3, "base_delay": "100ms", "max_delay": "1s", "multiplier": 2}}]}`,
+			want: []Config{
+				{
+					URL:    "https://example.com",
+					Method: http.MethodGet,
+					RetryPolicy: &RetryPolicy{
+						MaxAttempts: 3,
+						BaseDelay:   100 * time.Millisecond,
+						MaxDelay:    time.Second,
+						Multiplier:  2,
+					},
+				},
+			},
+		},
+		{
+			name:    "Unknown field",
+			input:   `{"monitors": [{"url": "https://example.com", "method": "GET", "body_contain": "x"}]}`,
+			wantErr: `unknown field "body_contain"`,
+		},
+		{
+			name:    "Unknown top-level field",
+			input:   `{"monitor": []}`,
+			wantErr: `unknown field "monitor"`,
+		},
+		{
+			name:    "Numeric duration",
+			input:   `{"monitors": [{"url": "https://example.com", "method": "GET", "request_timeout": 10}]}`,
+			wantErr: "duration must be a string",
+		},
+		{
+			name:    "Invalid duration",
+			input:   `{"monitors": [{"url": "https://example.com", "method": "GET", "request_timeout": "ten"}]}`,
+			wantErr: "invalid duration",
+		},
+		{
+			name:    "Unknown TLS version",
+			input:   `{"monitors": [{"url": "https://example.com", "method": "GET", "min_tls_version": "2.0"}]}`,
+			wantErr: `unknown TLS version "2.0"`,
+		},
+		{
+			name:    "Rejected by NewMonitor",
+			input:   `{"monitors": [{"url": "https://example.com"}, {"url": "example.org", "method": "GET"}]}`,
+			wantErr: "monitor 1:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConfig(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "monitors.json")
+	input := `{"monitors": [{"url": "https://example.com", "method": "HEAD"}]}`
+	if err := os.WriteFile(name, []byte(input), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadConfig(name)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Method != http.MethodHead {
+		t.Errorf("LoadConfig() = %+v", got)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadConfig() of missing file succeeded")
+	}
+}
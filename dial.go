@@ -0,0 +1,228 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoAddress indicates the host has no address in the address family
+	// required by Config.Network.
+	ErrNoAddress = errors.New("no address for network")
+
+	// ErrDNSTimeout indicates resolving the host took longer than
+	// Config.DNSTimeout.
+	ErrDNSTimeout = errors.New("DNS timeout")
+
+	// ErrConnectTimeout indicates connecting took longer than
+	// Config.DialTimeout.
+	ErrConnectTimeout = errors.New("connect timeout")
+
+	// ErrTLSHandshakeTimeout indicates the TLS handshake took longer than
+	// Config.TLSHandshakeTimeout.
+	ErrTLSHandshakeTimeout = errors.New("TLS handshake timeout")
+)
+
+// resolver resolves host names for DNSTimeout when Config sets no resolver.
+// Tests may replace it.
+var resolver = net.DefaultResolver
+
+// newDNSServerResolver returns a resolver that sends every query to the DNS
+// server at addr, which defaults to port 53.
+func newDNSServerResolver(addr string) (*net.Resolver, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid DNS server %q: %w", addr, err)
+	}
+
+	var dialer net.Dialer
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}, nil
+}
+
+// configResolver returns the resolver set by config.Resolver or
+// config.DNSServer, or nil for the system resolver.
+func configResolver(config Config) (*net.Resolver, error) {
+	if config.DNSServer == "" {
+		return config.Resolver, nil
+	}
+
+	if config.Resolver != nil {
+		return nil, fmt.Errorf("both Resolver and DNSServer set")
+	}
+
+	return newDNSServerResolver(config.DNSServer)
+}
+
+// dialFunc matches the signature of net.Dialer.DialContext.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// newDialFunc returns the function used to open connections for config.
+func newDialFunc(config Config) (dialFunc, error) {
+	var (
+		ip     net.IP
+		ipHost string
+	)
+	if config.DialIP != "" {
+		ip = net.ParseIP(config.DialIP)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid dial IP %q", config.DialIP)
+		}
+
+		u, err := url.Parse(config.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		ipHost = u.Hostname()
+	}
+
+	switch config.Network {
+	case "", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("invalid network %q", config.Network)
+	}
+
+	res, err := configResolver(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: config.DialTimeout, Resolver: res}
+	connect := dialer.DialContext
+	if config.DialContext != nil {
+		if res != nil {
+			return nil, fmt.Errorf("DialContext cannot be used with Resolver or DNSServer")
+		}
+		connect = withDialTimeout(config.DialContext, config.DialTimeout)
+	}
+
+	if config.SocketPath != "" {
+		if res != nil || ip != nil || config.Network != "" || config.DialContext != nil {
+			return nil, fmt.Errorf("SocketPath cannot be used with DialIP, Network, DialContext, Resolver or DNSServer")
+		}
+		connect = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", config.SocketPath)
+		}
+	}
+
+	if res == nil {
+		res = resolver
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if config.Network != "" {
+			network = config.Network
+		}
+
+		// Only connections to the URL host are pinned, not those to
+		// other hosts reached by redirects.
+		pinned := false
+		if ip != nil {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(host, ipHost) {
+				addr = net.JoinHostPort(ip.String(), port)
+				pinned = true
+			}
+		}
+
+		var (
+			conn net.Conn
+			err  error
+		)
+		if !pinned && config.DNSTimeout > 0 && config.SocketPath == "" {
+			conn, err = dialResolved(ctx, connect, res, config.DNSTimeout, network, addr)
+		} else {
+			conn, err = connect(ctx, network, addr)
+		}
+
+		var addrErr *net.AddrError
+		if errors.As(err, &addrErr) && addrErr.Err == "no suitable address found" {
+			return nil, fmt.Errorf("%w: %s has no %s address: %w", ErrNoAddress, addr, network, err)
+		}
+
+		var netErr net.Error
+		if config.DialTimeout > 0 && ctx.Err() == nil && !errors.Is(err, ErrDNSTimeout) &&
+			errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%w: connecting to %s took longer than %s: %w", ErrConnectTimeout, addr, config.DialTimeout, err)
+		}
+
+		return conn, err
+	}, nil
+}
+
+// withDialTimeout returns dial bounded by timeout, if positive, as
+// net.Dialer bounds its own connections.
+func withDialTimeout(dial dialFunc, timeout time.Duration) dialFunc {
+	if timeout <= 0 {
+		return dial
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return dial(ctx, network, addr)
+	}
+}
+
+// dialResolved resolves the host in addr with res, allowing at most
+// dnsTimeout, and then connects with connect to each of its addresses in
+// network in turn until one succeeds.
+func dialResolved(ctx context.Context, connect dialFunc, res *net.Resolver, dnsTimeout time.Duration, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if net.ParseIP(host) != nil {
+		return connect(ctx, network, addr)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+
+	ips, err := res.LookupIP(lookupCtx, "ip", host)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(lookupCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: resolving %s took longer than %s", ErrDNSTimeout, host, dnsTimeout)
+		}
+		return nil, err
+	}
+
+	var firstErr error
+	for _, ip := range ips {
+		if (network == "tcp4" && ip.To4() == nil) || (network == "tcp6" && ip.To4() != nil) {
+			continue
+		}
+
+		conn, err := connect(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil {
+		return nil, fmt.Errorf("%w: %s has no %s address", ErrNoAddress, addr, network)
+	}
+
+	return nil, firstErr
+}
@@ -0,0 +1,487 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckNetwork(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	tcpURL := "tcp://" + strings.TrimPrefix(ts.URL, "http://")
+
+	tests := []struct {
+		name    string
+		url     string
+		network string
+		wantErr error
+	}{
+		{name: "Any", url: ts.URL, network: "tcp"},
+		{name: "IPv4", url: ts.URL, network: "tcp4"},
+		{name: "IPv6 unavailable", url: ts.URL, network: "tcp6", wantErr: ErrNoAddress},
+		{name: "TCP check IPv6 unavailable", url: tcpURL, network: "tcp6", wantErr: ErrNoAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: tt.url, Method: http.MethodGet, Network: tt.network})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr != nil {
+				var checkErr *CheckError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &checkErr) || checkErr.Phase != PhaseDNS {
+					t.Errorf("Check() error = %v, want %v in DNS phase", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if want := ts.Listener.Addr().String(); got.RemoteAddr != want {
+				t.Errorf("RemoteAddr = %q, want %q", got.RemoteAddr, want)
+			}
+		})
+	}
+}
+
+func TestNewMonitor_InvalidNetwork(t *testing.T) {
+	_, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet, Network: "udp"})
+	if err == nil {
+		t.Errorf("NewMonitor() error = nil, want invalid network error")
+	}
+}
+
+func TestMonitor_CheckDNSTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// Host names missing from the hosts file are sent to a DNS server that
+	// never answers.
+	defer func(r *net.Resolver) { resolver = r }(resolver)
+	resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	port := ts.Listener.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name    string
+		host    string
+		network string
+		wantErr error
+	}{
+		{name: "Resolved", host: "localhost"},
+		{name: "Resolved IPv4", host: "localhost", network: "tcp4"},
+		{name: "No IPv6 address", host: "localhost", network: "tcp6", wantErr: ErrNoAddress},
+		{name: "Slow lookup", host: "slow.test", wantErr: ErrDNSTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            fmt.Sprintf("http://%s:%d", tt.host, port),
+				Method:         http.MethodGet,
+				RequestTimeout: 5 * time.Second,
+				DNSTimeout:     50 * time.Millisecond,
+				Network:        tt.network,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr != nil {
+				var checkErr *CheckError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &checkErr) || checkErr.Phase != PhaseDNS {
+					t.Errorf("Check() error = %v, want %v in DNS phase", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrAttemptTimeout) {
+					t.Errorf("Check() error = %v, want no ErrAttemptTimeout", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Timings.DNS <= 0 {
+				t.Errorf("Timings.DNS = %v, want > 0", got.Timings.DNS)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckTLSHandshakeTimeout(t *testing.T) {
+	// The listener accepts connections but never completes a handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer ln.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				<-done
+				conn.Close()
+			}()
+		}
+	}()
+
+	tests := []struct {
+		name                string
+		requestTimeout      time.Duration
+		tlsHandshakeTimeout time.Duration
+		hostOverride        string
+		wantErr             error
+	}{
+		{name: "Handshake timeout", requestTimeout: 5 * time.Second, tlsHandshakeTimeout: 50 * time.Millisecond, wantErr: ErrTLSHandshakeTimeout},
+		{name: "Handshake timeout with HostOverride", requestTimeout: 5 * time.Second, tlsHandshakeTimeout: 50 * time.Millisecond, hostOverride: "example.com", wantErr: ErrTLSHandshakeTimeout},
+		{name: "Attempt timeout first", requestTimeout: 50 * time.Millisecond, tlsHandshakeTimeout: 5 * time.Second, wantErr: ErrAttemptTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                 "https://" + ln.Addr().String(),
+				Method:              http.MethodGet,
+				RequestTimeout:      tt.requestTimeout,
+				TLSHandshakeTimeout: tt.tlsHandshakeTimeout,
+				HostOverride:        tt.hostOverride,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			_, err = m.Check(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != ErrTLSHandshakeTimeout {
+				return
+			}
+
+			if errors.Is(err, ErrAttemptTimeout) {
+				t.Errorf("Check() error = %v, want only %v", err, tt.wantErr)
+			}
+			var checkErr *CheckError
+			if !errors.As(err, &checkErr) || checkErr.Phase != PhaseTLS {
+				t.Errorf("Check() error = %v, want TLS error", err)
+			}
+		})
+	}
+}
+
+// startDNSServer starts a DNS server on UDP that answers A queries for the
+// names in hosts, which end with a dot, and returns its address. Other
+// names do not exist.
+func startDNSServer(t *testing.T, hosts map[string]net.IP) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			// Read the question name, one length-prefixed label at a time.
+			query := buf[:n]
+			end := 12
+			var name strings.Builder
+			for end < n && query[end] != 0 {
+				length := int(query[end])
+				name.Write(query[end+1 : end+1+length])
+				name.WriteByte('.')
+				end += 1 + length
+			}
+			end += 5 // the terminating zero, type and class
+			if end > n {
+				continue
+			}
+			qtype := query[end-4 : end-2]
+
+			ip, ok := hosts[strings.ToLower(name.String())]
+			flags := []byte{0x81, 0x80}
+			if !ok {
+				flags[1] |= 3 // NXDOMAIN
+			}
+			answers := byte(0)
+			if ok && qtype[0] == 0 && qtype[1] == 1 {
+				answers = 1
+			}
+
+			resp := append([]byte{}, query[:2]...)
+			resp = append(resp, flags...)
+			resp = append(resp, 0, 1, 0, answers, 0, 0, 0, 0)
+			resp = append(resp, query[12:end]...)
+			if answers > 0 {
+				resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+				resp = append(resp, ip.To4()...)
+			}
+
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestMonitor_CheckResolver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	dnsServer := startDNSServer(t, map[string]net.IP{"svc.internal.": net.IPv4(127, 0, 0, 1)})
+	port := ts.Listener.Addr().(*net.TCPAddr).Port
+
+	var dialer net.Dialer
+	customResolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, dnsServer)
+		},
+	}
+
+	tests := []struct {
+		name       string
+		host       string
+		resolver   *net.Resolver
+		dnsServer  string
+		dnsTimeout time.Duration
+		tcp        bool
+		wantErr    bool
+	}{
+		{name: "DNS server", host: "svc.internal", dnsServer: dnsServer},
+		{name: "Resolver", host: "svc.internal", resolver: customResolver},
+		{name: "Resolver with DNSTimeout", host: "svc.internal", resolver: customResolver, dnsTimeout: time.Second},
+		{name: "TCP check", host: "svc.internal", dnsServer: dnsServer, tcp: true},
+		{name: "Unknown host", host: "missing.internal", dnsServer: dnsServer, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, method := "http", http.MethodGet
+			if tt.tcp {
+				scheme, method = "tcp", ""
+			}
+
+			m, err := NewMonitor(Config{
+				URL:        fmt.Sprintf("%s://%s:%d", scheme, tt.host, port),
+				Method:     method,
+				Resolver:   tt.resolver,
+				DNSServer:  tt.dnsServer,
+				DNSTimeout: tt.dnsTimeout,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr {
+				var checkErr *CheckError
+				if !errors.As(err, &checkErr) || checkErr.Phase != PhaseDNS {
+					t.Errorf("Check() error = %v, want DNS error", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if want := []string{"127.0.0.1"}; !slices.Equal(got.ResolvedAddrs, want) {
+				t.Errorf("ResolvedAddrs = %q, want %q", got.ResolvedAddrs, want)
+			}
+		})
+	}
+
+	_, err := NewMonitor(Config{
+		URL:       ts.URL,
+		Method:    http.MethodGet,
+		Resolver:  customResolver,
+		DNSServer: dnsServer,
+	})
+	if err == nil {
+		t.Errorf("NewMonitor() with Resolver and DNSServer error = nil, want error")
+	}
+}
+
+func TestMonitor_CheckDialContext(t *testing.T) {
+	// Serve HTTP on a Unix socket, reachable only through DialContext.
+	socket := filepath.Join(t.TempDir(), "gomon.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("host=" + r.Host))
+	}))
+	ts.Listener = listener
+	ts.Start()
+	defer ts.Close()
+
+	var dialed []string
+	dialUnix := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+	blocked := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		dial        func(ctx context.Context, network, addr string) (net.Conn, error)
+		dialTimeout time.Duration
+		contains    string
+		wantDialed  string
+		wantErr     error
+	}{
+		{name: "HTTP", url: "http://gomon.test/", dial: dialUnix, contains: "host=gomon.test", wantDialed: "gomon.test:80"},
+		{name: "TCP", url: "tcp://gomon.test:25", dial: dialUnix, wantDialed: "gomon.test:25"},
+		{name: "DialTimeout", url: "http://gomon.test/", dial: blocked, dialTimeout: 50 * time.Millisecond, wantErr: ErrConnectTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed = nil
+			m, err := NewMonitor(Config{
+				URL:          tt.url,
+				Method:       http.MethodGet,
+				DialContext:  tt.dial,
+				DialTimeout:  tt.dialTimeout,
+				BodyContains: tt.contains,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.IsUp() {
+				t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+			}
+			if !slices.Equal(dialed, []string{tt.wantDialed}) {
+				t.Errorf("dialed %q, want %q", dialed, tt.wantDialed)
+			}
+		})
+	}
+
+	_, err = NewMonitor(Config{URL: "http://gomon.test/", Method: http.MethodGet, DialContext: dialUnix, DNSServer: "127.0.0.1"})
+	if err == nil {
+		t.Errorf("NewMonitor() with DialContext and DNSServer succeeded")
+	}
+}
+
+func TestMonitor_CheckSocketPath(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "gomon.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("host=" + r.Host))
+	}))
+	ts.Listener = listener
+	ts.Start()
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		config Config
+		wantUp bool
+	}{
+		{
+			name:   "Health",
+			config: Config{URL: "http://localhost/health", BodyContains: "host=localhost", DNSTimeout: time.Second},
+			wantUp: true,
+		},
+		{name: "Wrong path", config: Config{URL: "http://localhost/missing"}, wantUp: false},
+		{name: "Unresolvable host", config: Config{URL: "http://agent.invalid/health"}, wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Method = http.MethodGet
+			tt.config.SocketPath = socket
+			m, err := NewMonitor(tt.config)
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v, reasons %q", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+			if got.RemoteAddr != socket {
+				t.Errorf("RemoteAddr = %q, want %q", got.RemoteAddr, socket)
+			}
+			if elapsed := got.End.Sub(got.Start); elapsed <= 0 {
+				t.Errorf("response time = %s, want > 0", elapsed)
+			}
+		})
+	}
+
+	for _, config := range []Config{
+		{DialIP: "127.0.0.1"},
+		{Network: "tcp4"},
+		{DNSServer: "127.0.0.1"},
+		{Proxy: "http://proxy.example.com:3128"},
+	} {
+		config.URL = "http://localhost/health"
+		config.Method = http.MethodGet
+		config.SocketPath = socket
+		if _, err := NewMonitor(config); err == nil {
+			t.Errorf("NewMonitor(%+v) succeeded, want conflict with SocketPath", config)
+		}
+	}
+}
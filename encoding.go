@@ -0,0 +1,41 @@
+package gomon
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decodedBody returns a reader for the body of resp with any gzip or
+// deflate Content-Encoding removed. http.Transport only decompresses
+// responses to requests where it set Accept-Encoding itself. An unknown
+// encoding leaves the body as is.
+func decodedBody(resp *http.Response) (io.Reader, error) {
+	if resp.Uncompressed {
+		return resp.Body, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send a raw
+		// deflate stream.
+		br := bufio.NewReader(resp.Body)
+		if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return resp.Body, nil
+	}
+}
+
+// isZlibHeader reports whether b begins with a valid zlib header.
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
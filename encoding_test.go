@@ -0,0 +1,82 @@
+package gomon
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMonitor_CheckEncodedBody(t *testing.T) {
+	page := strings.Repeat("x", 100) + "welcome"
+
+	compress := func(encoding string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		}
+		w.Write([]byte(page))
+		w.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name         string
+		encoding     string
+		maxBodyBytes int64
+		wantUp       bool
+	}{
+		{name: "Gzip", encoding: "gzip", wantUp: true},
+		{name: "Deflate", encoding: "deflate", wantUp: true},
+		{name: "Raw deflate", encoding: "raw-deflate", wantUp: true},
+		{name: "Limit applies to decompressed size", encoding: "gzip", maxBodyBytes: 100, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := compress(tt.encoding)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", strings.TrimPrefix(tt.encoding, "raw-"))
+				w.Write(body)
+			}))
+			defer ts.Close()
+
+			// Disabling compression stops the transport from decoding
+			// the response itself.
+			client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+			m, err := NewMonitor(Config{
+				URL:          ts.URL,
+				Method:       http.MethodGet,
+				HTTPClient:   client,
+				BodyContains: "welcome",
+				MaxBodyBytes: tt.maxBodyBytes,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v (reasons %v)", got.Up, tt.wantUp, got.Reasons())
+			}
+			if tt.wantUp && got.ContentLength != int64(len(page)) {
+				t.Errorf("ContentLength = %d, want %d", got.ContentLength, len(page))
+			}
+		})
+	}
+}
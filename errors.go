@@ -0,0 +1,210 @@
+package gomon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Phase identifies the stage of a check where a failure occurred.
+type Phase int
+
+const (
+	PhaseRequest  Phase = iota // building the request
+	PhaseDNS                   // resolving the host name
+	PhaseConnect               // establishing the TCP connection
+	PhaseTLS                   // performing the TLS handshake
+	PhaseSend                  // sending the request and awaiting a response
+	PhaseResponse              // reading the response
+)
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseRequest:
+		return "Request"
+	case PhaseDNS:
+		return "DNS"
+	case PhaseConnect:
+		return "Connect"
+	case PhaseTLS:
+		return "TLS"
+	case PhaseSend:
+		return "Send"
+	case PhaseResponse:
+		return "Response"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+var (
+	// ErrAttemptTimeout indicates a single attempt exceeded RequestTimeout.
+	ErrAttemptTimeout = errors.New("attempt timed out")
+
+	// ErrDeadlineExceeded indicates the overall deadline, from the context
+	// passed to Check or from TotalTimeout, was exceeded.
+	ErrDeadlineExceeded = errors.New("overall deadline exceeded")
+)
+
+// FailureCode classifies the cause of a failed check, so that alerts can
+// tell, for example, a host name that does not exist from a service that
+// refuses connections. Use it with the Phase of a CheckError.
+type FailureCode string
+
+const (
+	FailureNone              FailureCode = ""
+	FailureNXDomain          FailureCode = "nxdomain"  // the host name does not exist
+	FailureDNS               FailureCode = "dns_error" // the host name could not be resolved
+	FailureConnectionRefused FailureCode = "connection_refused"
+	FailureConnectionReset   FailureCode = "connection_reset"
+	FailureUnreachable       FailureCode = "unreachable" // no route to the host or network
+	FailureTimeout           FailureCode = "timeout"
+	FailureTLS               FailureCode = "tls_error"
+	FailureCanceled          FailureCode = "canceled"
+	FailureOther             FailureCode = "other"
+)
+
+// classifyFailure returns the FailureCode for err.
+func classifyFailure(err error) FailureCode {
+	var (
+		dnsErr *net.DNSError
+		netErr net.Error
+	)
+
+	switch {
+	case err == nil:
+		return FailureNone
+	case errors.Is(err, context.Canceled):
+		return FailureCanceled
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return FailureNXDomain
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrDeadlineExceeded),
+		errors.Is(err, ErrAttemptTimeout), errors.Is(err, ErrSlowTransfer), isPhaseTimeout(err),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return FailureTimeout
+	case errors.As(err, &dnsErr), errors.Is(err, ErrNoAddress):
+		return FailureDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return FailureConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return FailureConnectionReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return FailureUnreachable
+	case isTLSError(err):
+		return FailureTLS
+	default:
+		return FailureOther
+	}
+}
+
+// CheckError describes a failed check. Use errors.As to inspect it.
+type CheckError struct {
+	Phase Phase
+	URL   string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *CheckError) Error() string {
+	var action string
+	switch e.Phase {
+	case PhaseRequest:
+		action = "create request"
+	case PhaseResponse:
+		action = "read response body"
+	default:
+		action = "send request"
+	}
+
+	return fmt.Sprintf("failed to %s for %q: %v", action, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
+
+// Code classifies the cause of the failure, such as FailureNXDomain when
+// the host name does not exist.
+func (e *CheckError) Code() FailureCode {
+	return classifyFailure(e.Err)
+}
+
+// classifyContextErr wraps err with the error of ctx, if ctx is done, so
+// that errors.Is reports context.Canceled or context.DeadlineExceeded. A
+// deadline is also marked with ErrDeadlineExceeded, and a timeout of a
+// single attempt with ErrAttemptTimeout.
+func classifyContextErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if !errors.Is(err, ctxErr) {
+			err = fmt.Errorf("%w: %w", ctxErr, err)
+		}
+
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %w", ErrDeadlineExceeded, err)
+		}
+
+		return err
+	}
+
+	var netErr net.Error
+	if !isPhaseTimeout(err) && errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %w", ErrAttemptTimeout, err)
+	}
+
+	return err
+}
+
+// isPhaseTimeout reports whether err is the timeout of a single phase, such
+// as DNSTimeout, rather than of the whole attempt.
+func isPhaseTimeout(err error) bool {
+	return errors.Is(err, ErrDNSTimeout) || errors.Is(err, ErrConnectTimeout) ||
+		errors.Is(err, ErrTLSHandshakeTimeout)
+}
+
+// sendPhase classifies an error returned by the HTTP client into a Phase.
+func sendPhase(err error) Phase {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) || errors.Is(err, ErrNoAddress) || errors.Is(err, ErrDNSTimeout) {
+		return PhaseDNS
+	}
+
+	if isTLSError(err) || errors.Is(err, ErrTLSHandshakeTimeout) {
+		return PhaseTLS
+	}
+
+	if errors.Is(err, ErrConnectTimeout) {
+		return PhaseConnect
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return PhaseConnect
+	}
+
+	return PhaseSend
+}
+
+// isTLSError reports whether err was caused by the TLS handshake.
+func isTLSError(err error) bool {
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
@@ -0,0 +1,215 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckError_Phase(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	closedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closedServer.URL
+	closedServer.Close()
+
+	// The .invalid TLD is reserved and never resolves. The fake DNS server
+	// answers so that the test does not depend on the network.
+	dnsServer := startDNSServer(t, nil)
+
+	tests := []struct {
+		name      string
+		url       string
+		dnsServer string
+		want      Phase
+		wantCode  FailureCode
+	}{
+		{name: "Connection refused", url: closedURL, want: PhaseConnect, wantCode: FailureConnectionRefused},
+		{name: "Untrusted certificate", url: tlsServer.URL, want: PhaseTLS, wantCode: FailureTLS},
+		{name: "Nonexistent host", url: "https://gomon-test.invalid", dnsServer: dnsServer, want: PhaseDNS, wantCode: FailureNXDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: tt.url, Method: http.MethodGet, DNSServer: tt.dnsServer})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+
+			var checkErr *CheckError
+			if !errors.As(err, &checkErr) {
+				t.Fatalf("Check() error = %v, want *CheckError", err)
+			}
+			if checkErr.Phase != tt.want {
+				t.Errorf("Phase = %v, want %v", checkErr.Phase, tt.want)
+			}
+			if checkErr.URL != m.config.URL {
+				t.Errorf("URL = %q, want %q", checkErr.URL, m.config.URL)
+			}
+			if code := checkErr.Code(); code != tt.wantCode {
+				t.Errorf("Code() = %q, want %q", code, tt.wantCode)
+			}
+			if got.ErrorCode != tt.wantCode {
+				t.Errorf("ErrorCode = %q, want %q", got.ErrorCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCheckError_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		requestTimeout time.Duration
+		totalTimeout   time.Duration
+		ctxTimeout     time.Duration
+		want           error
+	}{
+		{name: "Attempt timeout", requestTimeout: 50 * time.Millisecond, want: ErrAttemptTimeout},
+		{name: "Context deadline", requestTimeout: 5 * time.Second, ctxTimeout: 50 * time.Millisecond, want: ErrDeadlineExceeded},
+		{name: "Total timeout", requestTimeout: 5 * time.Second, totalTimeout: 50 * time.Millisecond, want: ErrDeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            ts.URL,
+				Method:         http.MethodGet,
+				RequestTimeout: tt.requestTimeout,
+				TotalTimeout:   tt.totalTimeout,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+
+			got, err := m.Check(ctx)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Check() error = %v, want %v", err, tt.want)
+			}
+
+			if got == nil {
+				t.Fatal("Check() result = nil, want partial result")
+			}
+			if got.Error != err.Error() || got.Up {
+				t.Errorf("Error = %q, Up = %v, want %q and false", got.Error, got.Up, err)
+			}
+			if got.ErrorCode != FailureTimeout {
+				t.Errorf("ErrorCode = %q, want %q", got.ErrorCode, FailureTimeout)
+			}
+			if elapsed := got.End.Sub(got.Start); elapsed < 40*time.Millisecond {
+				t.Errorf("End - Start = %v, want about 50ms", elapsed)
+			}
+			if got.CheckID == "" || got.Attempts != 1 {
+				t.Errorf("CheckID = %q, Attempts = %d, want both set", got.CheckID, got.Attempts)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRetriesAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+		}
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{
+		URL:            ts.URL,
+		Method:         http.MethodGet,
+		RequestTimeout: 50 * time.Millisecond,
+		RetryCount:     1,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", got.Attempts)
+	}
+}
+
+func TestCheckError_Canceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/body" {
+			w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+		}
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name  string
+		path  string
+		phase Phase
+	}{
+		{name: "Awaiting response", path: "/", phase: PhaseSend},
+		{name: "Reading body", path: "/body", phase: PhaseResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			m, err := NewMonitor(Config{
+				URL:      ts.URL + tt.path,
+				Method:   http.MethodGet,
+				ReadBody: true,
+				// Cancel once the response headers arrive, before the
+				// body is read.
+				SuccessFunc: func(*http.Response) bool {
+					cancel()
+					return true
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			if tt.phase == PhaseSend {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+
+			_, err = m.Check(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Check() error = %v, want context.Canceled", err)
+			}
+			if errors.Is(err, ErrDeadlineExceeded) {
+				t.Errorf("Check() error = %v, want no ErrDeadlineExceeded", err)
+			}
+
+			var checkErr *CheckError
+			if errors.As(err, &checkErr) && checkErr.Phase != tt.phase {
+				t.Errorf("Phase = %v, want %v", checkErr.Phase, tt.phase)
+			}
+		})
+	}
+}
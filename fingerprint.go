@@ -0,0 +1,33 @@
+package gomon
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// fingerprint returns the hex SHA-256 fingerprint of the DER encoding of
+// cert.
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// parseFingerprint converts a SHA-256 fingerprint written in hex, with or
+// without colons, or in base64 to the hex form returned by fingerprint.
+func parseFingerprint(s string) (string, error) {
+	if sum, err := hex.DecodeString(strings.ReplaceAll(s, ":", "")); err == nil && len(sum) == sha256.Size {
+		return hex.EncodeToString(sum), nil
+	}
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if sum, err := encoding.DecodeString(s); err == nil && len(sum) == sha256.Size {
+			return hex.EncodeToString(sum), nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid certificate fingerprint %q: want a SHA-256 hash in hex or base64", s)
+}
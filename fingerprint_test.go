@@ -0,0 +1,112 @@
+package gomon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFingerprint(t *testing.T) {
+	sum := sha256.Sum256([]byte("certificate"))
+	want := hex.EncodeToString(sum[:])
+
+	var colons []string
+	for i := 0; i < len(want); i += 2 {
+		colons = append(colons, strings.ToUpper(want[i:i+2]))
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "Hex", input: want},
+		{name: "Hex with colons", input: strings.Join(colons, ":")},
+		{name: "Base64", input: base64.StdEncoding.EncodeToString(sum[:])},
+		{name: "Raw URL base64", input: base64.RawURLEncoding.EncodeToString(sum[:])},
+		{name: "Too short", input: want[:62], wantErr: true},
+		{name: "Not encoded", input: "not a fingerprint", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFingerprint(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseFingerprint() = %q, want error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseFingerprint() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("parseFingerprint() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckCertFingerprint(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+
+	sum := sha256.Sum256(ts.Certificate().Raw)
+	pinned := hex.EncodeToString(sum[:])
+	other := strings.Repeat("00", sha256.Size)
+
+	tests := []struct {
+		name       string
+		url        string
+		expected   string
+		wantUp     bool
+		wantReason string
+	}{
+		{name: "Not pinned", url: ts.URL, wantUp: true},
+		{name: "Match", url: ts.URL, expected: pinned, wantUp: true},
+		{name: "Match base64", url: ts.URL, expected: base64.StdEncoding.EncodeToString(sum[:]), wantUp: true},
+		{name: "Mismatch", url: ts.URL, expected: other, wantReason: "certificate fingerprint " + pinned + ", want " + other},
+		{name: "No TLS", url: plain.URL, expected: pinned, wantReason: "TLS not used, want pinned certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                     tt.url,
+				Method:                  http.MethodGet,
+				IgnoreCert:              true,
+				ExpectedCertFingerprint: tt.expected,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v (reasons %q)", got.Up, tt.wantUp, got.Reasons())
+			}
+			if tt.wantReason != "" && !slices.Contains(got.Reasons(), tt.wantReason) {
+				t.Errorf("Reasons() = %q, want %q", got.Reasons(), tt.wantReason)
+			}
+			if got.CertInfo != nil && got.CertInfo.Fingerprint != pinned {
+				t.Errorf("CertInfo.Fingerprint = %q, want %q", got.CertInfo.Fingerprint, pinned)
+			}
+		})
+	}
+
+	if _, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, ExpectedCertFingerprint: "abc"}); err == nil {
+		t.Errorf("NewMonitor() with invalid fingerprint error = nil, want error")
+	}
+}
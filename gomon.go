@@ -1,13 +1,20 @@
 package gomon
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -21,79 +28,938 @@ type Config struct {
 	IgnoreCert         bool
 	DontFollowRedirect bool
 	UpStatusCodes      []int
-	//RequestBody string
+
+	// UpStatusRanges adds ranges of status codes to UpStatusCodes, such as
+	// "2xx", "200-299" or a single "204". A range starting with "!", such
+	// as "!204", excludes its codes even if listed elsewhere, so {"2xx",
+	// "!204"} accepts any 2xx status except 204. UpStatusCodes defaults to
+	// 200 and 201 only if UpStatusRanges includes no codes.
+	UpStatusRanges []string
+
+	// RequestBodyFile, if set, is the name of a file sent as the request
+	// body, with its size as the Content-Length. RequestBodyFunc, if set,
+	// instead returns the body to send, which is closed once sent, and
+	// the Content-Length is set only if the body has a Stat method, as an
+	// *os.File does. Either way the body is streamed and opened again for
+	// each attempt and for redirects that resend it.
+	RequestBodyFile string
+	RequestBodyFunc func() (io.ReadCloser, error)
+
+	// Headers are sent with each request. They are applied after the
+	// headers set by gomon, such as User-Agent, Accept and the
+	// cache-busting Cache-Control, Pragma and Expires, and replace any
+	// with the same name. Setting Cache-Control here therefore suppresses
+	// gomon's no-cache value, although Pragma, Expires and the cache-busting
+	// query parameter are still sent unless DisableCacheBusting is set.
+	// WithDefaultHeaders adds headers shared by several monitors.
 	Headers http.Header
+
+	// RetryCount is the number of additional attempts made after a
+	// failed attempt. RetryDelay is the wait before the first retry, and
+	// RetryBackoff, if greater than 1, multiplies the delay after each
+	// retry. By default only request errors are retried; set
+	// RetryDownStatus to also retry responses with an unacceptable status.
+	// RetryPolicy, if set, replaces these four fields with full control
+	// over attempts, delays and what is retried. Methods with side
+	// effects, such as POST, PATCH and DELETE, are not retried unless
+	// AllowUnsafeRetries is set, to avoid repeating them.
+	RetryCount         int
+	RetryDelay         time.Duration
+	RetryBackoff       float64
+	RetryDownStatus    bool
+	RetryPolicy        *RetryPolicy
+	AllowUnsafeRetries bool
+
+	// HTTPClient, if set, is used instead of a client built by NewMonitor,
+	// allowing connection pools and transports to be shared. The client is
+	// copied, not modified: RequestTimeout applies only if the client has
+	// no Timeout, DontFollowRedirect replaces its CheckRedirect, and
+	// MaxRedirects applies only if it has no CheckRedirect.
+	// IgnoreCert is ignored since TLS is configured by the client's
+	// Transport. For an HTTP URL, NewMonitor rejects the settings that
+	// only configure the transport: Proxy, DialIP, Network, Resolver,
+	// DNSServer, DNSTimeout, ClientCertFile and ClientKeyFile.
+	HTTPClient *http.Client
+
+	// CookieJar, if set, stores the cookies set by responses and sends
+	// them with later requests, such as a session cookie from a login
+	// endpoint. It makes the monitor stateful: each check depends on the
+	// ones before it, and a jar shared between monitors shares their
+	// sessions. It applies to HTTPClient only if the client has no Jar.
+	// Cookies are added to every request, in addition to any from the jar.
+	CookieJar http.CookieJar
+	Cookies   []*http.Cookie
+
+	// BasicAuthUser and BasicAuthPass, when both set, are sent using HTTP
+	// Basic Authentication.
+	BasicAuthUser string
+	BasicAuthPass string
+
+	// TokenProvider, if set, is called before each request, including
+	// each retry, and the token it returns is sent in an Authorization:
+	// Bearer header, replacing any in Headers. It is responsible for
+	// caching and refreshing the token. An error fails the check without
+	// sending the request.
+	TokenProvider func(ctx context.Context) (string, error)
+
+	// BodyContains, if set, must appear in the first MaxBodyBytes of the
+	// response body for the site to be considered up. A gzip or deflate
+	// encoded body is decompressed first, and MaxBodyBytes applies to the
+	// decompressed size. MaxBodyBytes defaults to 1 MiB. No more than
+	// MaxBodyBytes is read, and a longer body is truncated and reported by
+	// CheckResult.BodyTruncated.
+	BodyContains string
+	MaxBodyBytes int64
+
+	// UserAgent is sent as the User-Agent header and defaults to
+	// DefaultUserAgent. A User-Agent in Headers takes precedence.
+	UserAgent string
+
+	// Accept, AcceptEncoding and AcceptLanguage, if set, are sent as the
+	// Accept, Accept-Encoding and Accept-Language headers. As with
+	// UserAgent, a value in Headers takes precedence. Setting
+	// Accept-Encoding stops the transport from requesting and
+	// transparently decompressing gzip, although a gzip or deflate body is
+	// still decompressed when it is read. Use AcceptLanguage, such as
+	// "de-DE", with BodyContains to check localized content.
+	Accept         string
+	AcceptEncoding string
+	AcceptLanguage string
+
+	// DisableCacheBusting sends the request exactly as configured, without
+	// no-cache headers or the cache-busting query parameter.
+	DisableCacheBusting bool
+
+	// CacheBustParam names the query parameter, holding a unique value,
+	// that is appended to each request to bypass caches. Other parameters
+	// are sent unchanged. It defaults to DefaultCacheBustParam; choose a
+	// name the site ignores if that one has a meaning to it. The parameter
+	// is removed from the URLs recorded in CheckResult.
+	CacheBustParam string
+
+	// ExpectedHeaders lists response headers that must be present for the
+	// site to be considered up. An empty value only checks for presence.
+	ExpectedHeaders http.Header
+
+	// ExpectedTrailers lists response trailers that must be present, as
+	// for ExpectedHeaders. Trailers follow the body, so setting it reads
+	// the body, and a body truncated at MaxBodyBytes leaves them unread.
+	ExpectedTrailers http.Header
+
+	// SecurityHeaders names response headers, such as those in
+	// DefaultSecurityHeaders, that must be present for the site to be
+	// considered up. CheckResult.SecurityHeaders records which were found.
+	SecurityHeaders []string
+
+	// ResponseTimeThreshold, if positive, marks a check as degraded when
+	// the response takes longer than the threshold.
+	ResponseTimeThreshold time.Duration
+
+	// MaxClockSkew, if positive, marks the site as down when the Date
+	// header of the response differs from the local time by more than
+	// MaxClockSkew. A response without a valid Date header is not checked.
+	MaxClockSkew time.Duration
+
+	// TotalTimeout, if positive, bounds the entire check including
+	// retries, while RequestTimeout bounds each attempt. A deadline on the
+	// context passed to Check has the same effect.
+	TotalTimeout time.Duration
+
+	// MinTLSVersion, if set, is the oldest acceptable negotiated TLS
+	// version, such as tls.VersionTLS12. A response over an older version,
+	// or without TLS, marks the site as down.
+	MinTLSVersion uint16
+
+	// AllowCustomMethod permits a Method outside the standard HTTP methods.
+	AllowCustomMethod bool
+
+	// Proxy is the URL of the proxy to use, such as
+	// http://proxy.example.com:3128. If empty, the proxy is taken from the
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables. It
+	// cannot be used with HTTPClient.
+	Proxy string
+
+	// DialIP, if set, is the IP address connected to instead of the
+	// address resolved for the URL host. The URL host is still used for
+	// the Host header, TLS SNI, and certificate verification, allowing
+	// each node behind a load balancer to be checked individually. DialIP
+	// applies only to connections to the URL host, so redirects to other
+	// hosts resolve as usual. No proxy is used, and it cannot be used with
+	// Proxy or HTTPClient.
+	DialIP string
+
+	// CheckRevocation queries the certificate's OCSP responder and records
+	// the result in CertInfo.Revocation. It adds a request to each check,
+	// bounded by RequestTimeout. The request uses Proxy, Network,
+	// Resolver, DNSServer and DialTimeout, but not the settings that
+	// direct connections to the site, such as DialIP, SocketPath,
+	// DialContext, HostOverride and HTTPClient.
+	CheckRevocation bool
+
+	// Notifier, if set, is notified by Run when the site goes up or down.
+	// ConfirmCount is the number of consecutive checks that must agree
+	// before the state changes, which suppresses flapping. It defaults
+	// to 1.
+	Notifier     Notifier
+	ConfirmCount int
+
+	// SuccessFunc, if set, decides whether a response is acceptable in
+	// place of UpStatusCodes. It is called before the body is read. It may
+	// read the body, but must not close it, and any later BodyContains
+	// match only sees the bytes it left unread.
+	SuccessFunc func(*http.Response) bool
+
+	// OnResponse, if set, is called with each response after the status
+	// check and before the body is read, for assertions gomon does not
+	// support itself. An error marks the site as down, with the error as
+	// the reason. The hook may read up to MaxBodyBytes of the body, but
+	// must not close it, and later body checks only see the bytes it left
+	// unread.
+	OnResponse func(*http.Response) error
+
+	// Logger, if set, receives debug messages as each check progresses,
+	// including attempts, retries, redirects, TLS details and the outcome.
+	Logger *slog.Logger
+
+	// ClientCertFile and ClientKeyFile are PEM files containing a client
+	// certificate and its private key, presented for mutual TLS. They
+	// cannot be used with HTTPClient.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// RootCAs and RootCAFile, a PEM file of CA certificates, replace the
+	// system roots when verifying server certificates. If both are set,
+	// the certificates in RootCAFile are added to a copy of RootCAs.
+	RootCAs    *x509.CertPool
+	RootCAFile string
+
+	// MaxRedirects is the number of redirects followed before the check
+	// fails with a *RedirectError. It defaults to DefaultMaxRedirects. A
+	// redirect back to an already visited URL always fails.
+	MaxRedirects int
+
+	// Network forces the address family used to connect: "tcp4" for IPv4
+	// only, "tcp6" for IPv6 only, or "tcp", the default, for either. If
+	// the host has no address in the family, the check fails with
+	// ErrNoAddress. It cannot be used with HTTPClient.
+	Network string
+
+	// ReadBody reads the response body, up to MaxBodyBytes, even if no
+	// body check requires it, so that CheckResult.ContentLength is the
+	// number of bytes received after decompression for bodies too long to
+	// be read to the end otherwise.
+	ReadBody bool
+
+	// DisableKeepAlives opens a new connection for every check, so that
+	// each measures the full cost of DNS, connect and TLS. This is slower
+	// and places more load on the site than reusing a pooled connection.
+	DisableKeepAlives bool
+
+	// Jitter randomly varies each interval of Run by up to Jitter in
+	// either direction, spreading the load of many monitors. It is capped
+	// at half the interval.
+	Jitter time.Duration
+
+	// DNSTimeout, if positive, bounds the time spent resolving the host of
+	// each connection, which otherwise shares RequestTimeout with the rest
+	// of the attempt. A slow lookup fails with ErrDNSTimeout. It has no
+	// effect when DialIP is set, and cannot be used with HTTPClient.
+	DNSTimeout time.Duration
+
+	// Resolver, if set, resolves host names in place of the system
+	// resolver. DNSServer, if set, is the address of a DNS server, such as
+	// "10.0.0.2" or "10.0.0.2:5353", that receives every query instead;
+	// set at most one of them. The addresses found are recorded in
+	// CheckResult.ResolvedAddrs. Neither can be used with HTTPClient.
+	Resolver  *net.Resolver
+	DNSServer string
+
+	// DialTimeout and TLSHandshakeTimeout, if positive, bound connecting
+	// to the site and the TLS handshake, failing fast with
+	// ErrConnectTimeout or ErrTLSHandshakeTimeout instead of waiting for
+	// RequestTimeout. DialTimeout includes resolving the host unless
+	// DNSTimeout is set. Neither applies when HTTPClient is set.
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+
+	// DialContext, if set, opens connections in place of a net.Dialer,
+	// for example through a tunnel, over a Unix socket, or to an in-memory
+	// listener. It receives the address after DialIP and Network apply,
+	// and DialTimeout bounds it through its context. DNSTimeout resolves
+	// the host first and passes it IP addresses. It cannot be used with
+	// Resolver or DNSServer, and HTTP checks do not use it when
+	// HTTPClient is set.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// SocketPath, if set, is the Unix domain socket connected to in place
+	// of the URL host, as for a sidecar or local agent. The URL still
+	// supplies the scheme, path and Host header, so a placeholder host
+	// works, as in http://localhost/health. No proxy is used, and DNS is
+	// not consulted. It cannot be used with DialIP, Network, DialContext,
+	// Resolver, DNSServer or Proxy, and HTTP checks do not use it when
+	// HTTPClient is set.
+	SocketPath string
+
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout tune the pool
+	// of idle connections kept by the monitor's transport, as for
+	// http.Transport. Zero keeps the http.Transport defaults: any number
+	// of idle connections, two per host, kept until the server closes
+	// them. Each idle connection holds a socket and its buffers, so a
+	// process running thousands of monitors may lower the limits or set
+	// an IdleConnTimeout shorter than the check interval, at the cost of
+	// a new connection, with DNS, connect and TLS, for each check. They
+	// do not apply when HTTPClient is set or DisableKeepAlives is set.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// MinBodyBytes, if positive, is the smallest response body, after
+	// decompression, for the site to be considered up. It catches an
+	// error page served with a success status. CheckResult.ContentLength
+	// records the size read.
+	MinBodyBytes int64
+
+	// MinThroughput, if positive, is the slowest rate, in bytes per
+	// second, at which the response body may arrive. It catches a server
+	// that trickles the body just fast enough to stay within
+	// RequestTimeout. The rate is checked each second the body is read,
+	// and the check fails with ErrSlowTransfer once it falls below
+	// MinThroughput, so a body read within a second always passes.
+	// Setting it reads the body, whose throughput is recorded in
+	// CheckResult.Throughput.
+	MinThroughput int64
+
+	// ExpectHTTP2 marks the site as down unless the response is received
+	// over HTTP/2. The protocol is recorded in CheckResult.Proto.
+	ExpectHTTP2 bool
+
+	// HostOverride, if set, is sent as the Host header of the initial
+	// request in place of the URL host, for checking a virtual host by IP
+	// address. Unless HTTPClient is set, it is also the name sent for TLS
+	// SNI and verified during the handshake of connections to the URL
+	// host; connections to other hosts, such as redirects and an HTTPS
+	// proxy, use their own names, as do connections tunneled through a
+	// proxy. A Host in Headers is ignored, since Go sends the request's
+	// Host field instead.
+	HostOverride string
+
+	// SendCheckID sends CheckResult.CheckID in the CheckIDHeader request
+	// header, so that server logs can be matched with results.
+	SendCheckID bool
+
+	// CertVerifyHost, if set, is the host name CertInfo verifies the
+	// certificate against, in place of the host of the final URL. It does
+	// not change the name sent for SNI or verified during the handshake.
+	CertVerifyHost string
+
+	// ExpectedCertFingerprint, if set, pins the certificate: the SHA-256
+	// fingerprint of the leaf certificate of the final response, in hex,
+	// with or without colons, or base64, must match or the site is marked
+	// down. CertInfo.Fingerprint records the value to pin.
+	ExpectedCertFingerprint string
+
+	// ExpectedIssuers, if set, lists the acceptable issuers of the leaf
+	// certificate of the final response, to detect a certificate issued
+	// by an unexpected CA. The issuer DN, as in CertInfo.Issuer, must
+	// contain one of them, such as "O=Let's Encrypt" or the whole DN, or
+	// the site is marked down.
+	ExpectedIssuers []string
+
+	// AcceptRedirects treats any 3xx status as up, in addition to
+	// UpStatusCodes. Use it with DontFollowRedirect to check a site that
+	// is expected to redirect.
+	AcceptRedirects bool
+
+	// InvertResult reverses the status check, for endpoints that must
+	// refuse requests, such as a page that must stay protected: a status
+	// accepted by UpStatusCodes, UpStatusRanges or SuccessFunc marks the
+	// site down, and any other status marks it up. With the defaults, any
+	// status other than 200 or 201 is up, so set UpStatusCodes to the
+	// expected status, such as 403, with InvertResult unset, to require
+	// that status alone. The other expectations, such as BodyContains,
+	// are not inverted, and a check that gets no response is still down.
+	InvertResult bool
+
+	// ExpectedRedirectLocation, if set with DontFollowRedirect, is the
+	// Location a 3xx response must redirect to, such as the HTTPS form of
+	// an HTTP URL. A trailing "*" matches any Location with the preceding
+	// prefix. A relative Location is resolved against the request URL
+	// first. Responses that are not redirects are not checked.
+	ExpectedRedirectLocation string
+
+	// CaptureBodyOnFailure records the first BodySnippetBytes of the
+	// response body, after decompression, in CheckResult.BodySnippet when
+	// the status is not acceptable, such as an error page. The body of an
+	// acceptable response is not read for it. BodySnippetBytes defaults to
+	// DefaultBodySnippetBytes.
+	CaptureBodyOnFailure bool
+	BodySnippetBytes     int64
+
+	// CaptureHeaders names the response headers recorded in
+	// CheckResult.Headers. It defaults to DefaultCaptureHeaders. Use "*"
+	// to record all headers, or an empty, non-nil slice to record none.
+	CaptureHeaders []string
+}
+
+// Clone returns a copy of c. Its headers and slices are copied so that
+// changes to the copy do not affect c, while pointers such as HTTPClient,
+// RootCAs and Notifier, and functions, are shared.
+func (c Config) Clone() Config {
+	c.UpStatusCodes = slices.Clone(c.UpStatusCodes)
+	c.UpStatusRanges = slices.Clone(c.UpStatusRanges)
+	c.Headers = c.Headers.Clone()
+	c.ExpectedHeaders = c.ExpectedHeaders.Clone()
+	c.ExpectedTrailers = c.ExpectedTrailers.Clone()
+	c.CaptureHeaders = slices.Clone(c.CaptureHeaders)
+	c.SecurityHeaders = slices.Clone(c.SecurityHeaders)
+	c.Cookies = slices.Clone(c.Cookies)
+	c.ExpectedIssuers = slices.Clone(c.ExpectedIssuers)
+
+	return c
+}
+
+// WithHeader returns a copy of c with the header name set to value. The
+// Headers of c are not modified.
+func (c Config) WithHeader(name, value string) Config {
+	c.Headers = c.Headers.Clone()
+	if c.Headers == nil {
+		c.Headers = make(http.Header)
+	}
+	c.Headers.Set(name, value)
+
+	return c
 }
 
+// WithDefaultHeaders returns a copy of c with each header in defaults
+// added to Headers unless Headers already sets it, so that headers shared
+// by several monitors can be combined with each monitor's own. The Headers
+// of c are not modified.
+func (c Config) WithDefaultHeaders(defaults http.Header) Config {
+	headers := make(http.Header, len(c.Headers)+len(defaults))
+	for name, values := range c.Headers {
+		headers[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+
+	for name, values := range defaults {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := headers[name]; !ok {
+			headers[name] = slices.Clone(values)
+		}
+	}
+
+	c.Headers = headers
+	return c
+}
+
+// DefaultCacheBustParam is the cache-busting query parameter sent when
+// CacheBustParam is not set.
+const DefaultCacheBustParam = "nocache"
+
+// DefaultUserAgent is the User-Agent sent when none is configured.
+const DefaultUserAgent = "gomon/1.0"
+
+// DefaultBodySnippetBytes is the length of CheckResult.BodySnippet when
+// BodySnippetBytes is not set.
+const DefaultBodySnippetBytes = 1024
+
 // Monitor is a client used to monitor a site.
+//
+// A Monitor is safe for concurrent use by multiple goroutines. Its fields
+// are set by NewMonitor and not changed afterward, and each check builds
+// its own request and result. The functions in its Config, such as
+// RequestBodyFunc, TokenProvider, SuccessFunc and OnResponse, may be called
+// concurrently and must be safe for that use.
 type Monitor struct {
-	client *http.Client
-	config Config
+	client       *http.Client
+	ownsClient   bool // client was built by NewMonitor, not Config.HTTPClient
+	config       Config
+	tcp          bool
+	dial         dialFunc
+	log          *slog.Logger
+	retry        RetryPolicy
+	pin          string        // hex form of ExpectedCertFingerprint
+	statusRanges []statusRange // parsed from UpStatusRanges
+	ocsp         *http.Client  // for CheckRevocation
 }
 
 // CheckResult stores the results of a site check.
 type CheckResult struct {
-	URL        string
-	StatusCode int
-	Start      time.Time
-	End        time.Time
-	CertInfo   *CertInfo
+	CheckID          string           `json:"check_id"` // shared by all attempts of a check
+	URL              string           `json:"url"`
+	Method           string           `json:"method"`
+	FinalURL         string           `json:"final_url"`
+	RedirectCount    int              `json:"redirect_count"`
+	RedirectChain    []RedirectHop    `json:"redirect_chain,omitempty"`
+	RedirectLocation string           `json:"redirect_location,omitempty"` // Location of an unfollowed redirect
+	RemoteAddr       string           `json:"remote_addr,omitempty"`       // address connected to, such as one backend of a load balancer
+	ResolvedAddrs    []string         `json:"resolved_addrs,omitempty"`    // from the last DNS lookup, if any
+	ConnectionReused bool             `json:"connection_reused"`           // reused connections skip DNS, connect and TLS
+	StatusCode       int              `json:"status_code"`
+	Proto            string           `json:"proto,omitempty"`
+	ContentType      string           `json:"content_type"`
+	Error            string           `json:"error,omitempty"`          // why the attempt failed, if it did
+	Err              error            `json:"-"`                        // the *CheckError returned with the result
+	ErrorCode        FailureCode      `json:"error_code,omitempty"`     // classifies Err
+	ContentLength    int64            `json:"content_length"`           // body bytes received after decompression, or -1 if not read to the end
+	DeclaredLength   int64            `json:"declared_length"`          // from the Content-Length header, or -1 if absent
+	BodyTruncated    bool             `json:"body_truncated,omitempty"` // the body read exceeded MaxBodyBytes
+	Throughput       float64          `json:"throughput,omitempty"`     // bytes per second received while reading the body
+	BodySnippet      string           `json:"body_snippet,omitempty"`   // start of the body of a failed response
+	Headers          http.Header      `json:"headers,omitempty"`        // see Config.CaptureHeaders
+	Trailers         http.Header      `json:"trailers,omitempty"`       // received after a body read to the end
+	BodyMatched      bool             `json:"body_matched"`
+	HeadersMatched   bool             `json:"headers_matched"`
+	HeaderMismatches []HeaderMismatch `json:"header_mismatches,omitempty"`
+	SecurityHeaders  *SecurityHeaders `json:"security_headers,omitempty"` // see Config.SecurityHeaders
+	Up               bool             `json:"up"`
+	Degraded         bool             `json:"degraded"`
+	Attempts         int              `json:"attempts"`
+	Start            time.Time        `json:"start"`
+	End              time.Time        `json:"end"`
+	Timings          Timings          `json:"timings"`
+	ClockSkew        *time.Duration   `json:"-"` // server Date minus local time, or nil if unknown
+	CertInfo         *CertInfo        `json:"cert_info,omitempty"`
+
+	failures []string // reasons the site is not up
 }
 
 // CertInfo contains certificate details for HTTPS checks.
 type CertInfo struct {
-	Subject   string
-	Issuer    string
-	ValidFrom time.Time
-	ValidTo   time.Time
-	DNSNames  []string
-	IsValid   bool
-	ErrorMsg  string
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	ValidFrom time.Time `json:"valid_from"`
+	ValidTo   time.Time `json:"valid_to"`
+	DNSNames  []string  `json:"dns_names"`
+	IsValid   bool      `json:"is_valid"`
+	ErrorCode CertError `json:"error_code,omitempty"`
+	ErrorMsg  string    `json:"error_msg,omitempty"`
+
+	// SelfSigned reports whether the certificate is signed by its own
+	// key, whether or not it is trusted.
+	SelfSigned bool `json:"self_signed"`
+
+	// Fingerprint is the hex SHA-256 fingerprint of the certificate.
+	Fingerprint string `json:"fingerprint"`
+
+	TLSVersion  string `json:"tls_version"`
+	CipherSuite string `json:"cipher_suite"`
+
+	Revocation RevocationStatus `json:"revocation,omitempty"`
+
+	Chain []CertSummary `json:"chain"`
 }
 
-// noRedirect disables HTTP redirects.
-func noRedirect(req *http.Request, via []*http.Request) error {
-	return http.ErrUseLastResponse
+// CertSummary describes one certificate in the chain presented by a server.
+type CertSummary struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	ValidFrom time.Time `json:"valid_from"`
+	ValidTo   time.Time `json:"valid_to"`
 }
 
-// NewMonitor creates and configures a new Site monitor instance.
+// NewMonitor creates and configures a new Site monitor instance. The
+// config is cloned, so later changes to its headers do not affect the
+// monitor.
 func NewMonitor(config Config) (*Monitor, error) {
+	config = config.Clone()
+
 	if config.RequestTimeout == 0 {
 		config.RequestTimeout = 10 * time.Second
 	}
 
-	if len(config.UpStatusCodes) == 0 {
+	statusRanges, err := parseStatusRanges(config.UpStatusRanges)
+	if err != nil {
+		return nil, err
+	}
+
+	included := func(r statusRange) bool { return !r.exclude }
+	if len(config.UpStatusCodes) == 0 && !slices.ContainsFunc(statusRanges, included) {
 		config.UpStatusCodes = []int{200, 201}
 	}
 
-	if config.Method == "" {
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+
+	if config.CacheBustParam == "" {
+		config.CacheBustParam = DefaultCacheBustParam
+	}
+
+	if config.MaxRedirects == 0 {
+		config.MaxRedirects = DefaultMaxRedirects
+	}
+
+	if config.ConfirmCount == 0 {
+		config.ConfirmCount = 1
+	}
+
+	if config.CaptureHeaders == nil {
+		config.CaptureHeaders = DefaultCaptureHeaders
+	}
+
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+
+	if config.BodySnippetBytes == 0 {
+		config.BodySnippetBytes = DefaultBodySnippetBytes
+	}
+
+	tcp := isTCPURL(config.URL)
+
+	if config.Method == "" && !tcp {
 		return nil, fmt.Errorf("missing HTTP method")
 	}
 
+	if !tcp && !config.AllowCustomMethod {
+		if err := validateMethod(config.Method); err != nil {
+			return nil, err
+		}
+	}
+
 	if config.RequestTimeout < 0 {
 		return nil, fmt.Errorf("negative timeout")
 	}
 
+	if config.RetryCount < 0 {
+		return nil, fmt.Errorf("negative retry count")
+	}
+
+	if config.RetryDelay < 0 {
+		return nil, fmt.Errorf("negative retry delay")
+	}
+
+	if config.MaxRedirects < 0 {
+		return nil, fmt.Errorf("negative max redirects")
+	}
+
+	if config.DNSTimeout < 0 {
+		return nil, fmt.Errorf("negative DNS timeout")
+	}
+
+	if config.DialTimeout < 0 {
+		return nil, fmt.Errorf("negative dial timeout")
+	}
+
+	if config.TLSHandshakeTimeout < 0 {
+		return nil, fmt.Errorf("negative TLS handshake timeout")
+	}
+
+	if config.MaxIdleConns < 0 || config.MaxIdleConnsPerHost < 0 {
+		return nil, fmt.Errorf("negative max idle connections")
+	}
+
+	if config.IdleConnTimeout < 0 {
+		return nil, fmt.Errorf("negative idle connection timeout")
+	}
+
+	if config.Jitter < 0 {
+		return nil, fmt.Errorf("negative jitter")
+	}
+
+	if config.ConfirmCount < 0 {
+		return nil, fmt.Errorf("negative confirm count")
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative max body bytes")
+	}
+
+	if config.BodySnippetBytes < 0 {
+		return nil, fmt.Errorf("negative body snippet bytes")
+	}
+
+	if config.MinBodyBytes < 0 {
+		return nil, fmt.Errorf("negative min body bytes")
+	}
+
+	if config.MinThroughput < 0 {
+		return nil, fmt.Errorf("negative min throughput")
+	}
+
+	if config.MinBodyBytes > config.MaxBodyBytes {
+		return nil, fmt.Errorf("min body bytes %d exceeds max body bytes %d", config.MinBodyBytes, config.MaxBodyBytes)
+	}
+
+	if config.TotalTimeout < 0 {
+		return nil, fmt.Errorf("negative total timeout")
+	}
+
+	if config.RequestBodyFile != "" && config.RequestBodyFunc != nil {
+		return nil, fmt.Errorf("both RequestBodyFile and RequestBodyFunc set")
+	}
+
+	if config.BasicAuthUser != "" && config.TokenProvider != nil {
+		return nil, fmt.Errorf("both BasicAuthUser and TokenProvider set")
+	}
+
+	if config.HTTPClient != nil && !tcp {
+		if err := checkHTTPClient(config); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.ResponseTimeThreshold < 0 {
+		return nil, fmt.Errorf("negative response time threshold")
+	}
+
+	if config.MaxClockSkew < 0 {
+		return nil, fmt.Errorf("negative max clock skew")
+	}
+
+	if config.RootCAFile != "" {
+		roots, err := loadRootCAs(config.RootCAs, config.RootCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = roots
+	}
+
 	validURL, err := sanitizeURL(config.URL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 	config.URL = validURL
 
-	client := &http.Client{
-		Timeout: config.RequestTimeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: config.IgnoreCert,
-			},
-		},
+	if tcp {
+		if err := validateTCPURL(config.URL); err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+	}
+
+	dial, err := newDialFunc(config)
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := retryPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var pin string
+	if config.ExpectedCertFingerprint != "" {
+		pin, err = parseFingerprint(config.ExpectedCertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var client *http.Client
+	if config.HTTPClient != nil {
+		clientCopy := *config.HTTPClient
+		client = &clientCopy
+		if client.Timeout == 0 {
+			client.Timeout = config.RequestTimeout
+		}
+		if client.Jar == nil {
+			client.Jar = config.CookieJar
+		}
+	} else {
+		transport, err := newTransport(config, dial)
+		if err != nil {
+			return nil, err
+		}
+
+		client = &http.Client{
+			Timeout:   config.RequestTimeout,
+			Transport: transport,
+			Jar:       config.CookieJar,
+		}
 	}
 
 	if config.DontFollowRedirect {
 		client.CheckRedirect = noRedirect
+	} else if client.CheckRedirect == nil {
+		client.CheckRedirect = limitRedirects(config.MaxRedirects, cacheBustParam(config))
+	}
+
+	var ocspClient *http.Client
+	if config.CheckRevocation {
+		ocspClient, err = newOCSPClient(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Monitor{
+		client:       client,
+		ownsClient:   config.HTTPClient == nil,
+		config:       config,
+		tcp:          tcp,
+		dial:         dial,
+		log:          newLogger(config),
+		retry:        retry,
+		pin:          pin,
+		statusRanges: statusRanges,
+		ocsp:         ocspClient,
+	}, nil
+}
+
+// WithURL returns a new Monitor with the configuration of m, cloned with
+// Config.Clone, for a different URL. The new monitor has its own
+// connections unless Config.HTTPClient is set.
+func (m *Monitor) WithURL(url string) (*Monitor, error) {
+	config := m.config.Clone()
+	config.URL = url
+
+	return NewMonitor(config)
+}
+
+// Close releases the idle connections held by the monitor. Connections of
+// a Config.HTTPClient are left open, since its transport may be shared.
+// Close should be called at most once, and the monitor must not be used
+// afterward. It always returns nil.
+func (m *Monitor) Close() error {
+	if m.ownsClient {
+		m.client.CloseIdleConnections()
+	}
+	if m.ocsp != nil {
+		m.ocsp.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+// standardMethods lists the HTTP methods accepted without AllowCustomMethod.
+var standardMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// retryableMethods lists the methods retried without AllowUnsafeRetries.
+var retryableMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPut,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// validateMethod returns an error if method is not a standard HTTP method.
+func validateMethod(method string) error {
+	if slices.Contains(standardMethods, method) {
+		return nil
+	}
+
+	upper := strings.ToUpper(method)
+	if slices.Contains(standardMethods, upper) {
+		return fmt.Errorf("invalid HTTP method %q: methods are case-sensitive, use %q", method, upper)
 	}
 
-	return &Monitor{client: client, config: config}, nil
+	return fmt.Errorf("unknown HTTP method %q", method)
+}
+
+// checkHTTPClient returns an error naming the settings of config that only
+// configure the transport built by NewMonitor, which Config.HTTPClient
+// replaces.
+func checkHTTPClient(config Config) error {
+	settings := []struct {
+		name string
+		set  bool
+	}{
+		{"Proxy", config.Proxy != ""},
+		{"DialIP", config.DialIP != ""},
+		{"Network", config.Network != ""},
+		{"Resolver", config.Resolver != nil},
+		{"DNSServer", config.DNSServer != ""},
+		{"DNSTimeout", config.DNSTimeout > 0},
+		{"ClientCertFile", config.ClientCertFile != ""},
+		{"ClientKeyFile", config.ClientKeyFile != ""},
+	}
+
+	var names []string
+	for _, setting := range settings {
+		if setting.set {
+			names = append(names, setting.name)
+		}
+	}
+
+	if len(names) > 0 {
+		return fmt.Errorf("%s cannot be used with HTTPClient", strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// newTransport creates the HTTP transport described by config that opens
+// connections with dial.
+func newTransport(config Config, dial dialFunc) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dial,
+		TLSClientConfig:     tlsConfig,
+		DisableKeepAlives:   config.DisableKeepAlives,
+		TLSHandshakeTimeout: config.TLSHandshakeTimeout,
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		// A custom dialer or TLS config otherwise disables HTTP/2.
+		ForceAttemptHTTP2: true,
+	}
+
+	if config.SocketPath != "" {
+		if config.Proxy != "" {
+			return nil, fmt.Errorf("SocketPath cannot be used with Proxy")
+		}
+		transport.Proxy = nil
+	}
+
+	if config.DialIP != "" {
+		if config.Proxy != "" {
+			return nil, fmt.Errorf("DialIP cannot be used with Proxy")
+		}
+		transport.Proxy = nil
+	}
+
+	if config.HostOverride != "" {
+		u, err := url.Parse(config.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		transport.DialTLSContext = dialTLSFunc(dial, transport, config.TLSHandshakeTimeout, u.Hostname(), hostname(config.HostOverride))
+	}
+
+	if config.Proxy != "" {
+		transport.Proxy, err = proxyFunc(config.Proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return transport, nil
+}
+
+// proxyFunc returns the http.Transport proxy function for the proxy URL,
+// or for the environment if proxy is empty.
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := sanitizeURL(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy: %w", err)
+	}
+
+	parsedProxyURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy: %w", err)
+	}
+
+	return http.ProxyURL(parsedProxyURL), nil
 }
 
 // sanitizeURL validates and returns a sanitized URL string.
@@ -116,7 +982,17 @@ func sanitizeURL(rawURL string) (string, error) {
 
 // isSuccessStatus determines if status code is acceptable based on config.
 func (m *Monitor) isSuccessStatus(code int) bool {
-	if len(m.config.UpStatusCodes) == 0 {
+	for _, r := range m.statusRanges {
+		if r.exclude && r.contains(code) {
+			return false
+		}
+	}
+
+	if m.config.AcceptRedirects && code >= 300 && code < 400 {
+		return true
+	}
+
+	if len(m.config.UpStatusCodes) == 0 && len(m.statusRanges) == 0 {
 		return code >= 200 && code < 300
 	}
 
@@ -126,51 +1002,483 @@ func (m *Monitor) isSuccessStatus(code int) bool {
 		}
 	}
 
+	for _, r := range m.statusRanges {
+		if !r.exclude && r.contains(code) {
+			return true
+		}
+	}
+
 	return false
 }
 
 // Check executes an HTTP request to the configured URL and returns the result.
+// For a tcp:// URL, Check only opens a TCP connection.
+// Failed attempts are retried as configured. Failures are reported as a
+// *CheckError. The result is never nil: a failed check also returns a
+// result with whatever is known, such as its URL and timing, and with
+// CheckResult.Err and CheckResult.Error describing the failure.
 func (m *Monitor) Check(ctx context.Context) (*CheckResult, error) {
-	result := CheckResult{URL: m.config.URL}
+	if m.config.TotalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.config.TotalTimeout)
+		defer cancel()
+	}
+
+	checkID := newCheckID()
+
+	for attempt := 1; ; attempt++ {
+		m.logger().DebugContext(ctx, "check started", "check_id", checkID, "attempt", attempt)
+
+		result, err := m.checkOnce(ctx, checkID)
+		result.CheckID = checkID
+		result.Attempts = attempt
+
+		if attempt >= m.retry.MaxAttempts || !m.shouldRetry(ctx, result, err) {
+			m.logOutcome(ctx, result, err)
+			return result, err
+		}
+
+		delay := m.retry.delay(attempt)
+
+		if err != nil {
+			m.logger().DebugContext(ctx, "retrying", "attempt", attempt, "delay", delay, "error", err)
+		} else {
+			m.logger().DebugContext(ctx, "retrying", "attempt", attempt, "delay", delay, "reasons", result.Reasons())
+		}
+
+		if !sleep(ctx, delay) {
+			m.logOutcome(ctx, result, err)
+			return result, err
+		}
+	}
+}
+
+// shouldRetry determines if the outcome of an attempt warrants another.
+func (m *Monitor) shouldRetry(ctx context.Context, result *CheckResult, err error) bool {
+	if !m.tcp && !m.config.AllowUnsafeRetries && !slices.Contains(retryableMethods, m.config.Method) {
+		return false
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+
+	return m.retry.Retryable(err, result)
+}
+
+// sleep waits for the duration d or until ctx is done, reporting whether
+// the full duration elapsed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
 
-	req, err := http.NewRequestWithContext(ctx, m.config.Method, m.config.URL, nil)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// checkOnce performs a single check attempt.
+func (m *Monitor) checkOnce(ctx context.Context, checkID string) (*CheckResult, error) {
+	if m.tcp {
+		return m.checkTCP(ctx)
+	}
+
+	result := CheckResult{URL: m.config.URL, Method: m.config.Method}
+	trace := &tracer{}
+
+	// Canceling the request with a cause reports a slow transfer as such.
+	reqCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	req, err := m.newRequest(trace.withTrace(reqCtx))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for %q: %w", m.config.URL, err)
+		result.Start = time.Now()
+		result.End = result.Start
+		return result.failed(&CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err})
 	}
 
-	// Add cache-busting headers to the request
-	req.Header.Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	req.Header.Set("Pragma", "no-cache")
-	req.Header.Set("Expires", "0")
-	req.URL.RawQuery = fmt.Sprintf("nocache=%d", time.Now().UnixNano())
+	if err := m.prepareRequest(req, checkID); err != nil {
+		result.Start = time.Now()
+		result.End = result.Start
+		return result.failed(&CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err})
+	}
 
 	result.Start = time.Now()
+	trace.start = result.Start
 	resp, err := m.client.Do(req)
 	result.End = time.Now()
+	result.Timings = trace.result()
+	result.RemoteAddr, result.ConnectionReused = trace.conn()
+	result.ResolvedAddrs = trace.resolved()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request for %q: %w", m.config.URL, err)
+		if m.config.TLSHandshakeTimeout > 0 && trace.handshakeTimedOut() {
+			err = fmt.Errorf("%w: handshake took longer than %s: %w", ErrTLSHandshakeTimeout, m.config.TLSHandshakeTimeout, err)
+		}
+
+		// Keep the redirects followed before the limit or loop stopped
+		// the check.
+		var redirectErr *RedirectError
+		if errors.As(err, &redirectErr) {
+			result.RedirectChain = redirectErr.Hops
+			result.RedirectCount = len(redirectErr.Hops)
+		}
+
+		return result.failed(&CheckError{Phase: sendPhase(err), URL: m.config.URL, Err: classifyContextErr(ctx, err)})
 	}
 	defer resp.Body.Close()
 
+	result.FinalURL = m.config.URL
+	result.RedirectChain = redirectChain(resp.Request, cacheBustParam(m.config))
+	result.RedirectCount = len(result.RedirectChain)
+	for _, hop := range result.RedirectChain {
+		m.logger().DebugContext(ctx, "redirect", "from", hop.URL, "status", hop.StatusCode)
+	}
+	if result.RedirectCount > 0 {
+		result.FinalURL = stripCacheBust(resp.Request.URL, cacheBustParam(m.config))
+	}
+
 	result.StatusCode = resp.StatusCode
+	result.Proto = resp.Proto
+	result.Up = true
+	var statusOK bool
+	if m.config.SuccessFunc != nil {
+		statusOK = m.config.SuccessFunc(resp)
+	} else {
+		statusOK = m.isSuccessStatus(resp.StatusCode)
+	}
+	if m.config.InvertResult {
+		statusOK = !statusOK
+	}
+	if !statusOK {
+		switch {
+		case m.config.InvertResult:
+			result.fail("status code %d accepted, want it rejected with InvertResult", resp.StatusCode)
+		case m.config.SuccessFunc != nil:
+			result.fail("response with status code %d rejected by SuccessFunc", resp.StatusCode)
+		default:
+			result.fail("unexpected status code %d", resp.StatusCode)
+		}
+	}
 
-	// Discard response body
-	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
-		return nil, fmt.Errorf("failed to read response body for %q: %w", m.config.URL, err)
+	result.RedirectLocation = redirectLocation(resp, cacheBustParam(m.config))
+	if want := m.config.ExpectedRedirectLocation; want != "" && m.config.DontFollowRedirect &&
+		result.RedirectLocation != "" && !matchLocation(want, result.RedirectLocation) {
+		result.fail("redirect to %s, want %s", result.RedirectLocation, want)
+	}
+
+	if m.config.OnResponse != nil {
+		if err := m.config.OnResponse(resp); err != nil {
+			result.fail("response rejected by OnResponse: %v", err)
+		}
+	}
+
+	threshold := m.config.ResponseTimeThreshold
+	result.Degraded = threshold > 0 && result.End.Sub(result.Start) > threshold
+
+	result.ClockSkew = clockSkew(resp.Header, result.End)
+	if maxSkew := m.config.MaxClockSkew; maxSkew > 0 && result.ClockSkew != nil && result.ClockSkew.Abs() > maxSkew {
+		result.fail("clock skew %s exceeds %s", *result.ClockSkew, maxSkew)
+	}
+
+	// Match response headers
+	result.HeaderMismatches = matchHeaders(m.config.ExpectedHeaders, resp.Header)
+	result.HeadersMatched = len(result.HeaderMismatches) == 0
+	for _, mismatch := range result.HeaderMismatches {
+		result.fail("header %s", mismatch)
+	}
+
+	if len(m.config.SecurityHeaders) > 0 {
+		result.SecurityHeaders = checkSecurityHeaders(m.config.SecurityHeaders, resp.Header)
+		for _, name := range result.SecurityHeaders.Missing {
+			result.fail("missing security header %s", name)
+		}
+	}
+
+	result.Headers = captureHeaders(m.config.CaptureHeaders, resp.Header)
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.DeclaredLength = resp.ContentLength
+	result.ContentLength = -1
+
+	// Read the response body in full only when needed. Otherwise only
+	// its start is read, so that a short body leaves the connection
+	// reusable while a large page is not downloaded.
+	result.BodyMatched = true
+	var body []byte
+	if m.readsBody() {
+		bodyMeter := newMeter(resp.Body)
+		resp.Body = bodyMeter
+		stop := watchThroughput(bodyMeter, m.config.MinThroughput, cancel)
+
+		// MaxBodyBytes limits the decompressed body.
+		decoded, err := decodedBody(resp)
+		if err == nil {
+			// Reading one byte past the limit detects a longer body.
+			body, err = io.ReadAll(io.LimitReader(decoded, pastLimit(m.config.MaxBodyBytes)))
+		}
+		stop()
+		result.Throughput = bodyMeter.throughput()
+		if err != nil {
+			if cause := context.Cause(reqCtx); errors.Is(cause, ErrSlowTransfer) {
+				err = cause
+			}
+			return result.failed(&CheckError{Phase: PhaseResponse, URL: m.config.URL, Err: classifyContextErr(ctx, err)})
+		}
+		if int64(len(body)) > m.config.MaxBodyBytes {
+			body = body[:m.config.MaxBodyBytes]
+			result.BodyTruncated = true
+		}
+		result.ContentLength = int64(len(body))
+
+		// Trailers arrive only once the body has been read to the end.
+		result.Trailers = receivedTrailers(resp.Trailer)
+		for _, mismatch := range matchHeaders(m.config.ExpectedTrailers, resp.Trailer) {
+			result.fail("trailer %s", mismatch)
+		}
+
+		if result.ContentLength < m.config.MinBodyBytes {
+			result.fail("body is %d bytes, want at least %d", result.ContentLength, m.config.MinBodyBytes)
+		}
+
+		if m.config.BodyContains != "" {
+			result.BodyMatched = bytes.Contains(body, []byte(m.config.BodyContains))
+			if !result.BodyMatched {
+				result.fail("body does not contain %q", m.config.BodyContains)
+			}
+		}
+	} else {
+		limit := int64(drainBytes)
+		if m.config.CaptureBodyOnFailure && !statusOK {
+			limit = max(limit, m.config.BodySnippetBytes)
+		}
+
+		var complete bool
+		body, complete = drainBody(resp, limit)
+		if complete {
+			result.ContentLength = int64(len(body))
+		}
+	}
+
+	if m.config.CaptureBodyOnFailure && !statusOK {
+		result.BodySnippet = string(body[:min(int64(len(body)), m.config.BodySnippetBytes)])
 	}
 
 	// Process certificate information
 	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
 		// extract host from response to handle redirects
-		result.CertInfo = certInfo(resp.TLS, resp.Request.URL.Hostname())
+		host := resp.Request.URL.Hostname()
+		switch {
+		case m.config.CertVerifyHost != "":
+			host = m.config.CertVerifyHost
+		case m.config.HostOverride != "" && strings.EqualFold(host, req.URL.Hostname()):
+			host = hostname(m.config.HostOverride)
+		}
+		result.CertInfo = certInfo(resp.TLS, host, m.config.RootCAs)
+		m.logger().DebugContext(ctx, "tls",
+			"version", result.CertInfo.TLSVersion,
+			"cipher_suite", result.CertInfo.CipherSuite,
+			"subject", result.CertInfo.Subject,
+			"valid_to", result.CertInfo.ValidTo,
+			"valid", result.CertInfo.IsValid)
+
+		if m.config.CheckRevocation {
+			m.checkRevocation(ctx, result.CertInfo, resp.TLS)
+		}
+	}
+
+	if m.pin != "" {
+		switch {
+		case result.CertInfo == nil:
+			result.fail("TLS not used, want pinned certificate")
+		case result.CertInfo.Fingerprint != m.pin:
+			result.fail("certificate fingerprint %s, want %s", result.CertInfo.Fingerprint, m.pin)
+		}
+	}
+
+	if len(m.config.ExpectedIssuers) > 0 {
+		switch {
+		case result.CertInfo == nil:
+			result.fail("TLS not used, want certificate issuer %q", m.config.ExpectedIssuers)
+		case !matchIssuer(m.config.ExpectedIssuers, result.CertInfo.Issuer):
+			result.fail("certificate issuer %q is not one of %q", result.CertInfo.Issuer, m.config.ExpectedIssuers)
+		}
+	}
+
+	if m.config.ExpectHTTP2 && resp.ProtoMajor != 2 {
+		result.fail("protocol %s, want HTTP/2", resp.Proto)
+	}
+
+	if minVersion := m.config.MinTLSVersion; minVersion != 0 {
+		switch {
+		case resp.TLS == nil:
+			result.fail("TLS not used, want %s or later", tls.VersionName(minVersion))
+		case resp.TLS.Version < minVersion:
+			result.fail("TLS version %s, want %s or later",
+				tls.VersionName(resp.TLS.Version), tls.VersionName(minVersion))
+		}
 	}
 
 	return &result, nil
 }
 
-// certInfo extracts certificate details and verifies the validity.
-func certInfo(tlsState *tls.ConnectionState, host string) *CertInfo {
+// prepareRequest adds the configured headers, cache busting, Host and
+// credentials to req. It returns an error only if TokenProvider fails.
+func (m *Monitor) prepareRequest(req *http.Request, checkID string) error {
+	// Add cache-busting headers to the request
+	if !m.config.DisableCacheBusting {
+		req.Header.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		req.Header.Set("Pragma", "no-cache")
+		req.Header.Set("Expires", "0")
+
+		// Append the parameter, rather than re-encoding the query, so
+		// that the other parameters are sent exactly as configured.
+		param := url.QueryEscape(m.config.CacheBustParam) + "=" + strconv.FormatInt(time.Now().UnixNano(), 10)
+		if req.URL.RawQuery != "" {
+			param = req.URL.RawQuery + "&" + param
+		}
+		req.URL.RawQuery = param
+	}
+
+	req.Header.Set("User-Agent", m.config.UserAgent)
+	if m.config.Accept != "" {
+		req.Header.Set("Accept", m.config.Accept)
+	}
+	if m.config.AcceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", m.config.AcceptEncoding)
+	}
+	if m.config.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", m.config.AcceptLanguage)
+	}
+
+	// Configured headers replace any set above.
+	for name, values := range m.config.Headers {
+		req.Header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+
+	for _, cookie := range m.config.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	if m.config.HostOverride != "" {
+		req.Host = m.config.HostOverride
+	}
+
+	if m.config.SendCheckID {
+		req.Header.Set(CheckIDHeader, checkID)
+	}
+
+	// Closing the connection after the response also keeps it out of the
+	// pool of a caller-supplied HTTPClient.
+	req.Close = m.config.DisableKeepAlives
+
+	if m.config.BasicAuthUser != "" && m.config.BasicAuthPass != "" {
+		req.SetBasicAuth(m.config.BasicAuthUser, m.config.BasicAuthPass)
+	}
+
+	if m.config.TokenProvider != nil {
+		token, err := m.config.TokenProvider(req.Context())
+		if err != nil {
+			return fmt.Errorf("token provider: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
+
+// drainBytes is how much of a response body that no check needs is read
+// before it is closed. A body no longer than this is read to the end,
+// which returns the connection to the pool for reuse and gives its size.
+const drainBytes = 4 << 10
+
+// pastLimit returns one more than n, the number of bytes to read to detect
+// a body longer than n, without overflowing for math.MaxInt64.
+func pastLimit(n int64) int64 {
+	if n < math.MaxInt64 {
+		n++
+	}
+
+	return n
+}
+
+// drainBody returns up to n bytes of the decompressed body of resp and
+// reports whether they are the whole body. As no check needs the body, a
+// read error is not returned, and the body read is reported incomplete.
+func drainBody(resp *http.Response, n int64) ([]byte, bool) {
+	decoded, err := decodedBody(resp)
+	if err != nil {
+		return nil, false
+	}
+
+	// Reading one byte past the limit detects a longer body.
+	body, err := io.ReadAll(io.LimitReader(decoded, pastLimit(n)))
+	if err != nil || int64(len(body)) > n {
+		return body[:min(int64(len(body)), n)], false
+	}
+
+	return body, true
+}
+
+// readsBody reports whether Check reads the response body.
+func (m *Monitor) readsBody() bool {
+	return m.config.ReadBody || m.config.BodyContains != "" || m.config.MinBodyBytes > 0 ||
+		m.config.MinThroughput > 0 || len(m.config.ExpectedTrailers) > 0
+}
+
+// cacheBustParam returns the cache-busting parameter sent for config, or
+// "" if cache busting is disabled.
+func cacheBustParam(config Config) string {
+	if config.DisableCacheBusting {
+		return ""
+	}
+
+	return config.CacheBustParam
+}
+
+// stripCacheBust returns u as a string without the cache-busting parameter
+// param, leaving the other parameters as they are. An empty param leaves u
+// unchanged.
+func stripCacheBust(u *url.URL, param string) string {
+	if param == "" || u.RawQuery == "" {
+		return u.String()
+	}
+
+	pairs := slices.DeleteFunc(strings.Split(u.RawQuery, "&"), func(pair string) bool {
+		key, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		return err == nil && key == param
+	})
+
+	stripped := *u
+	stripped.RawQuery = strings.Join(pairs, "&")
+
+	return stripped.String()
+}
+
+// IsUp reports whether the check received an acceptable status code and
+// the response met any configured content expectations.
+// It is safe to call on a nil or partially populated result.
+func (result *CheckResult) IsUp() bool {
+	return result != nil && result.Up
+}
+
+// matchIssuer reports whether issuer contains one of expected.
+func matchIssuer(expected []string, issuer string) bool {
+	return slices.ContainsFunc(expected, func(want string) bool {
+		return strings.Contains(issuer, want)
+	})
+}
+
+// certInfo extracts certificate details and verifies the validity against
+// roots, or the system roots if nil.
+func certInfo(tlsState *tls.ConnectionState, host string, roots *x509.CertPool) *CertInfo {
 	cert := tlsState.PeerCertificates[0]
 	certInfo := &CertInfo{
 		Subject:   cert.Subject.String(),
@@ -179,27 +1487,48 @@ func certInfo(tlsState *tls.ConnectionState, host string) *CertInfo {
 		ValidTo:   cert.NotAfter,
 		DNSNames:  cert.DNSNames,
 		IsValid:   true,
+
+		SelfSigned:  isSelfSigned(cert),
+		Fingerprint: fingerprint(cert),
+
+		TLSVersion:  tls.VersionName(tlsState.Version),
+		CipherSuite: tls.CipherSuiteName(tlsState.CipherSuite),
+	}
+
+	for _, chainCert := range tlsState.PeerCertificates {
+		certInfo.Chain = append(certInfo.Chain, CertSummary{
+			Subject:   chainCert.Subject.String(),
+			Issuer:    chainCert.Issuer.String(),
+			ValidFrom: chainCert.NotBefore,
+			ValidTo:   chainCert.NotAfter,
+		})
 	}
 
 	// Check certificate validity
 	now := time.Now()
 	if now.Before(cert.NotBefore) {
 		certInfo.IsValid = false
+		certInfo.ErrorCode = CertErrorNotYetValid
 		certInfo.ErrorMsg = fmt.Sprintf("certificate not yet valid: %s", cert.NotBefore)
 		return certInfo
 	}
 	if now.After(cert.NotAfter) {
 		certInfo.IsValid = false
+		certInfo.ErrorCode = CertErrorExpired
 		certInfo.ErrorMsg = fmt.Sprintf("certificate has expired: %s", cert.NotAfter)
 		return certInfo
 	}
 
 	// Perform standard x509 verification
-	roots, err := x509.SystemCertPool()
-	if err != nil {
-		certInfo.IsValid = false
-		certInfo.ErrorMsg = fmt.Sprintf("error loading system root certificates: %v", err)
-		return certInfo
+	if roots == nil {
+		var err error
+		roots, err = x509.SystemCertPool()
+		if err != nil {
+			certInfo.IsValid = false
+			certInfo.ErrorCode = CertErrorOther
+			certInfo.ErrorMsg = fmt.Sprintf("error loading system root certificates: %v", err)
+			return certInfo
+		}
 	}
 
 	opts := x509.VerifyOptions{
@@ -213,12 +1542,165 @@ func certInfo(tlsState *tls.ConnectionState, host string) *CertInfo {
 
 	if _, err := cert.Verify(opts); err != nil {
 		certInfo.IsValid = false
-		certInfo.ErrorMsg = fmt.Sprintf("hostname verification failed: %v", err)
+		certInfo.ErrorCode = ClassifyCertError(err)
+		certInfo.ErrorMsg = certErrorMessage(certInfo.ErrorCode, err)
+		if certInfo.SelfSigned && certInfo.ErrorCode == CertErrorUnknownAuthority {
+			certInfo.ErrorMsg = "self-signed certificate: " + err.Error()
+		}
 	}
 
 	return certInfo
 }
 
+// DaysUntilExpiry returns the number of whole days until the certificate
+// expires. The value is negative for an expired certificate.
+func (c *CertInfo) DaysUntilExpiry() int {
+	return int(math.Floor(time.Until(c.ValidTo).Hours() / 24))
+}
+
+// ExpiresWithin reports whether the certificate expires within d.
+func (c *CertInfo) ExpiresWithin(d time.Duration) bool {
+	return time.Until(c.ValidTo) < d
+}
+
+// checkRevocation records the revocation status of the leaf certificate,
+// invalidating a revoked certificate.
+func (m *Monitor) checkRevocation(ctx context.Context, info *CertInfo, tlsState *tls.ConnectionState) {
+	info.Revocation = revocationStatus(ctx, m.ocsp, m.config.RequestTimeout,
+		tlsState.PeerCertificates[0], issuerOf(tlsState))
+
+	if info.Revocation == RevocationRevoked {
+		info.IsValid = false
+		info.ErrorCode = CertErrorRevoked
+		info.ErrorMsg = "certificate has been revoked"
+	}
+}
+
+// String implements the Stringer interface for CertInfo.
+func (c *CertInfo) String() string {
+	const timeFormat = time.DateTime
+
+	var builder strings.Builder
+
+	builder.WriteString("Subject: ")
+	builder.WriteString(c.Subject)
+	builder.WriteString("\n")
+
+	builder.WriteString("Issuer: ")
+	builder.WriteString(c.Issuer)
+	builder.WriteString("\n")
+
+	if len(c.DNSNames) > 0 {
+		builder.WriteString("DNS Names: ")
+		builder.WriteString(strings.Join(c.DNSNames, ", "))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("Valid: ")
+	builder.WriteString(strconv.FormatBool(c.IsValid))
+	builder.WriteString("\n")
+
+	if c.ErrorMsg != "" {
+		builder.WriteString("Error: ")
+		builder.WriteString(c.ErrorMsg)
+		builder.WriteString("\n")
+	}
+
+	if c.SelfSigned {
+		builder.WriteString("Self-Signed: true\n")
+	}
+
+	if c.Fingerprint != "" {
+		builder.WriteString("Fingerprint: ")
+		builder.WriteString(c.Fingerprint)
+		builder.WriteString("\n")
+	}
+
+	if c.TLSVersion != "" {
+		builder.WriteString("TLS: ")
+		builder.WriteString(c.TLSVersion)
+		builder.WriteString(" (")
+		builder.WriteString(c.CipherSuite)
+		builder.WriteString(")\n")
+	}
+
+	if c.Revocation != "" {
+		builder.WriteString("Revocation: ")
+		builder.WriteString(string(c.Revocation))
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("From ")
+	builder.WriteString(c.ValidFrom.Format(timeFormat))
+	builder.WriteString(" to ")
+	builder.WriteString(c.ValidTo.Format(timeFormat))
+	builder.WriteString("\n")
+
+	if len(c.Chain) > 1 {
+		builder.WriteString("Chain:\n")
+		for _, summary := range c.Chain {
+			builder.WriteString("  ")
+			builder.WriteString(summary.Subject)
+			builder.WriteString(" (to ")
+			builder.WriteString(summary.ValidTo.Format(timeFormat))
+			builder.WriteString(")\n")
+		}
+	}
+
+	if days := c.DaysUntilExpiry(); days >= 0 {
+		builder.WriteString("Expires in: ")
+		builder.WriteString(strconv.Itoa(days))
+		builder.WriteString(" days\n")
+	} else {
+		builder.WriteString("Expired: ")
+		builder.WriteString(strconv.Itoa(-days))
+		builder.WriteString(" days ago\n")
+	}
+
+	return builder.String()
+}
+
+// Summary returns a single line describing the result, such as
+// "UP https://example.com 200 143ms cert-ok", for logs where String is too
+// verbose. The certificate status is omitted when there is no certificate,
+// and an invalid certificate is shown with its CertError, such as
+// "cert-expired". A failed check ends with its FailureCode, such as
+// "nxdomain".
+func (result *CheckResult) Summary() string {
+	if result == nil {
+		return "DOWN no result"
+	}
+
+	state := "UP"
+	if !result.Up {
+		state = "DOWN"
+	}
+
+	fields := []string{
+		state,
+		result.URL,
+		strconv.Itoa(result.StatusCode),
+		result.End.Sub(result.Start).Round(time.Millisecond).String(),
+	}
+
+	if cert := result.CertInfo; cert != nil {
+		switch {
+		case cert.IsValid:
+			fields = append(fields, "cert-ok")
+		case cert.ErrorCode != CertErrorNone:
+			fields = append(fields, "cert-"+string(cert.ErrorCode))
+		default:
+			fields = append(fields, "cert-invalid")
+		}
+	}
+
+	if result.ErrorCode != FailureNone {
+		fields = append(fields, string(result.ErrorCode))
+	}
+
+	return strings.Join(fields, " ")
+}
+
 // String implements the Stringer interface for MonitorResult.
 func (result *CheckResult) String() string {
 	const timeFormat = time.DateTime
@@ -229,12 +1711,124 @@ func (result *CheckResult) String() string {
 	builder.WriteString(result.URL)
 	builder.WriteString("\n")
 
+	if result.FinalURL != "" && result.FinalURL != result.URL {
+		builder.WriteString("Final URL: ")
+		builder.WriteString(result.FinalURL)
+		builder.WriteString(" (")
+		builder.WriteString(strconv.Itoa(result.RedirectCount))
+		builder.WriteString(" redirects)\n")
+
+		for _, hop := range result.RedirectChain {
+			builder.WriteString("  Redirect: ")
+			builder.WriteString(hop.String())
+			builder.WriteString("\n")
+		}
+	}
+
+	if result.RedirectLocation != "" {
+		builder.WriteString("Location: ")
+		builder.WriteString(result.RedirectLocation)
+		builder.WriteString("\n")
+	}
+
+	if result.RemoteAddr != "" {
+		builder.WriteString("Remote Address: ")
+		builder.WriteString(result.RemoteAddr)
+		if result.ConnectionReused {
+			builder.WriteString(" (reused connection)")
+		}
+		builder.WriteString("\n")
+	}
+
 	builder.WriteString("Status: ")
 	builder.WriteString(strconv.Itoa(result.StatusCode))
 	builder.WriteString(" (")
 	builder.WriteString(http.StatusText(result.StatusCode)) // String status code
 	builder.WriteString(")\n")
 
+	if result.Proto != "" {
+		builder.WriteString("Protocol: ")
+		builder.WriteString(result.Proto)
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("Content: ")
+	if result.ContentType != "" {
+		builder.WriteString(result.ContentType)
+		builder.WriteString(", ")
+	}
+	if result.ContentLength >= 0 {
+		builder.WriteString(strconv.FormatInt(result.ContentLength, 10))
+		builder.WriteString(" bytes")
+		if result.BodyTruncated {
+			builder.WriteString(" (truncated)")
+		}
+		if result.Throughput > 0 {
+			builder.WriteString(", ")
+			builder.WriteString(strconv.FormatFloat(result.Throughput, 'f', 0, 64))
+			builder.WriteString(" bytes/s")
+		}
+	} else {
+		builder.WriteString("unknown length")
+	}
+	if result.DeclaredLength >= 0 && result.DeclaredLength != result.ContentLength && !result.BodyTruncated {
+		builder.WriteString(" (declared ")
+		builder.WriteString(strconv.FormatInt(result.DeclaredLength, 10))
+		builder.WriteString(" bytes)")
+	}
+	builder.WriteString("\n")
+
+	if len(result.Headers) > 0 {
+		builder.WriteString("Headers:\n")
+		for _, name := range slices.Sorted(maps.Keys(result.Headers)) {
+			builder.WriteString("  ")
+			builder.WriteString(name)
+			builder.WriteString(": ")
+			builder.WriteString(strings.Join(result.Headers[name], ", "))
+			builder.WriteString("\n")
+		}
+	}
+
+	if sh := result.SecurityHeaders; sh != nil {
+		builder.WriteString("Security Headers: ")
+		builder.WriteString(strconv.Itoa(len(sh.Present)))
+		builder.WriteString(" of ")
+		builder.WriteString(strconv.Itoa(len(sh.Present) + len(sh.Missing)))
+		builder.WriteString(" present")
+		if len(sh.Missing) > 0 {
+			builder.WriteString(", missing ")
+			builder.WriteString(strings.Join(sh.Missing, ", "))
+		}
+		builder.WriteString("\n")
+	}
+
+	if len(result.Trailers) > 0 {
+		builder.WriteString("Trailers:\n")
+		for _, name := range slices.Sorted(maps.Keys(result.Trailers)) {
+			builder.WriteString("  ")
+			builder.WriteString(name)
+			builder.WriteString(": ")
+			builder.WriteString(strings.Join(result.Trailers[name], ", "))
+			builder.WriteString("\n")
+		}
+	}
+
+	builder.WriteString("Healthy: ")
+	builder.WriteString(strconv.FormatBool(result.Healthy()))
+	builder.WriteString("\n")
+
+	for _, reason := range result.Reasons() {
+		builder.WriteString("  Reason: ")
+		builder.WriteString(reason)
+		builder.WriteString("\n")
+	}
+
+	if result.BodySnippet != "" {
+		builder.WriteString("Body Snippet: ")
+		builder.WriteString(strconv.Quote(result.BodySnippet))
+		builder.WriteString("\n")
+	}
+
 	builder.WriteString("Start: ")
 	builder.WriteString(result.Start.Format(timeFormat))
 	builder.WriteString("\n")
@@ -247,23 +1841,33 @@ func (result *CheckResult) String() string {
 	builder.WriteString(result.End.Sub(result.Start).String())
 	builder.WriteString("\n")
 
-	if result.CertInfo != nil {
-		builder.WriteString("Certificate Info:\n")
-		builder.WriteString("  Valid: ")
-		builder.WriteString(strconv.FormatBool(result.CertInfo.IsValid))
+	builder.WriteString("Timings:\n")
+	builder.WriteString("  DNS: ")
+	builder.WriteString(result.Timings.DNS.String())
+	builder.WriteString("\n")
+	builder.WriteString("  Connect: ")
+	builder.WriteString(result.Timings.Connect.String())
+	builder.WriteString("\n")
+	builder.WriteString("  TLS: ")
+	builder.WriteString(result.Timings.TLSHandshake.String())
+	builder.WriteString("\n")
+	builder.WriteString("  First Byte: ")
+	builder.WriteString(result.Timings.FirstByte.String())
+	builder.WriteString("\n")
+
+	if result.ClockSkew != nil {
+		builder.WriteString("Clock Skew: ")
+		builder.WriteString(result.ClockSkew.String())
 		builder.WriteString("\n")
+	}
 
-		if result.CertInfo.ErrorMsg != "" {
-			builder.WriteString("  Error: ")
-			builder.WriteString(result.CertInfo.ErrorMsg)
+	if result.CertInfo != nil {
+		builder.WriteString("Certificate Info:\n")
+		for _, line := range strings.Split(strings.TrimSuffix(result.CertInfo.String(), "\n"), "\n") {
+			builder.WriteString("  ")
+			builder.WriteString(line)
 			builder.WriteString("\n")
 		}
-
-		builder.WriteString("  From ")
-		builder.WriteString(result.CertInfo.ValidFrom.Format(timeFormat))
-		builder.WriteString(" to ")
-		builder.WriteString(result.CertInfo.ValidTo.Format(timeFormat))
-		builder.WriteString("\n")
 	}
 
 	return builder.String()
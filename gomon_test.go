@@ -2,7 +2,23 @@ package gomon
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -41,6 +57,31 @@ func TestNewMonitor(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Lowercase HTTP method",
+			config: Config{
+				URL:    "https://example.com",
+				Method: "get",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown HTTP method",
+			config: Config{
+				URL:    "https://example.com",
+				Method: "GETT",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Custom HTTP method allowed",
+			config: Config{
+				URL:               "https://example.com",
+				Method:            "PROPFIND",
+				AllowCustomMethod: true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "Negative timeout",
 			config: Config{
@@ -50,6 +91,16 @@ func TestNewMonitor(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Basic auth and token provider",
+			config: Config{
+				URL:           "https://example.com",
+				Method:        http.MethodGet,
+				BasicAuthUser: "admin",
+				TokenProvider: func(context.Context) (string, error) { return "token", nil },
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,3 +217,2005 @@ func TestMonitor_Check(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckResult_Summary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(143*time.Millisecond + 400*time.Microsecond)
+
+	tests := []struct {
+		name   string
+		result *CheckResult
+		want   string
+	}{
+		{
+			name:   "Up without certificate",
+			result: &CheckResult{URL: "http://example.com", StatusCode: 200, Up: true, Start: start, End: end},
+			want:   "UP http://example.com 200 143ms",
+		},
+		{
+			name: "Up with certificate",
+			result: &CheckResult{URL: "https://example.com", StatusCode: 200, Up: true, Start: start, End: end,
+				CertInfo: &CertInfo{IsValid: true}},
+			want: "UP https://example.com 200 143ms cert-ok",
+		},
+		{
+			name: "Down with expired certificate",
+			result: &CheckResult{URL: "https://example.com", StatusCode: 503, Start: start, End: end,
+				CertInfo: &CertInfo{ErrorCode: CertErrorExpired}},
+			want: "DOWN https://example.com 503 143ms cert-expired",
+		},
+		{
+			name:   "Failed",
+			result: &CheckResult{URL: "https://example.invalid", Start: start, End: end, ErrorCode: FailureNXDomain},
+			want:   "DOWN https://example.invalid 0 143ms nxdomain",
+		},
+		{
+			name:   "Nil",
+			result: nil,
+			want:   "DOWN no result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResult_IsUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   bool
+	}{
+		{name: "Up status", status: http.StatusOK, want: true},
+		{name: "Down status", status: http.StatusServiceUnavailable, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Up != tt.want || got.IsUp() != tt.want {
+				t.Errorf("Up = %v, IsUp() = %v, want %v", got.Up, got.IsUp(), tt.want)
+			}
+		})
+	}
+
+	var nilResult *CheckResult
+	if nilResult.IsUp() {
+		t.Errorf("IsUp() on nil result = true, want false")
+	}
+}
+
+func TestMonitor_CheckRetry(t *testing.T) {
+	tests := []struct {
+		name            string
+		failures        int
+		retryCount      int
+		retryDownStatus bool
+		wantAttempts    int
+		wantUp          bool
+	}{
+		{name: "No retry needed", failures: 0, retryCount: 3, retryDownStatus: true, wantAttempts: 1, wantUp: true},
+		{name: "Recovers after retries", failures: 2, retryCount: 3, retryDownStatus: true, wantAttempts: 3, wantUp: true},
+		{name: "Retries exhausted", failures: 5, retryCount: 2, retryDownStatus: true, wantAttempts: 3, wantUp: false},
+		{name: "Down status not retried", failures: 2, retryCount: 3, retryDownStatus: false, wantAttempts: 1, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer ts.Close()
+
+			m, err := NewMonitor(Config{
+				URL:             ts.URL,
+				Method:          http.MethodGet,
+				RetryCount:      tt.retryCount,
+				RetryDelay:      time.Millisecond,
+				RetryBackoff:    2,
+				RetryDownStatus: tt.retryDownStatus,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantUp)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRetryUnsafeMethods(t *testing.T) {
+	tests := []struct {
+		method       string
+		allowUnsafe  bool
+		wantAttempts int
+	}{
+		{method: http.MethodGet, wantAttempts: 3},
+		{method: http.MethodPut, wantAttempts: 3},
+		{method: http.MethodPost, wantAttempts: 1},
+		{method: http.MethodPatch, wantAttempts: 1},
+		{method: http.MethodDelete, wantAttempts: 1},
+		{method: http.MethodDelete, allowUnsafe: true, wantAttempts: 3},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s allowUnsafe=%v", tt.method, tt.allowUnsafe), func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                ts.URL,
+				Method:             tt.method,
+				RetryCount:         2,
+				RetryDelay:         time.Millisecond,
+				RetryDownStatus:    true,
+				AllowUnsafeRetries: tt.allowUnsafe,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRetryCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{
+		URL:             ts.URL,
+		Method:          http.MethodGet,
+		RetryCount:      5,
+		RetryDelay:      time.Hour,
+		RetryDownStatus: true,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	got, err := m.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", got.Attempts)
+	}
+}
+
+func TestCertInfo_Expiry(t *testing.T) {
+	tests := []struct {
+		name       string
+		validTo    time.Time
+		wantDays   int
+		within     time.Duration
+		wantWithin bool
+	}{
+		{
+			name:       "Valid for 12 days",
+			validTo:    time.Now().Add(12*24*time.Hour + time.Hour),
+			wantDays:   12,
+			within:     30 * 24 * time.Hour,
+			wantWithin: true,
+		},
+		{
+			name:       "Valid beyond window",
+			validTo:    time.Now().Add(60*24*time.Hour + time.Hour),
+			wantDays:   60,
+			within:     30 * 24 * time.Hour,
+			wantWithin: false,
+		},
+		{
+			name:       "Expired 3 days ago",
+			validTo:    time.Now().Add(-3*24*time.Hour + time.Hour),
+			wantDays:   -3,
+			within:     0,
+			wantWithin: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CertInfo{ValidTo: tt.validTo}
+			if got := c.DaysUntilExpiry(); got != tt.wantDays {
+				t.Errorf("DaysUntilExpiry() = %d, want %d", got, tt.wantDays)
+			}
+			if got := c.ExpiresWithin(tt.within); got != tt.wantWithin {
+				t.Errorf("ExpiresWithin(%v) = %v, want %v", tt.within, got, tt.wantWithin)
+			}
+		})
+	}
+}
+
+func TestCertInfo_String(t *testing.T) {
+	info := &CertInfo{
+		Subject:  "CN=example.com",
+		Issuer:   "CN=Example CA",
+		DNSNames: []string{"example.com", "www.example.com"},
+		IsValid:  true,
+		ValidTo:  time.Now().Add(10*24*time.Hour + time.Hour),
+	}
+
+	got := info.String()
+	for _, want := range []string{
+		"Subject: CN=example.com\n",
+		"Issuer: CN=Example CA\n",
+		"DNS Names: example.com, www.example.com\n",
+		"Valid: true\n",
+		"Expires in: 10 days\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+
+	result := &CheckResult{CertInfo: info}
+	if !strings.Contains(result.String(), "Certificate Info:\n  Subject: CN=example.com\n") {
+		t.Errorf("CheckResult.String() does not include indented certificate info:\n%s", result.String())
+	}
+}
+
+// roundTripFunc adapts a function to the http.RoundTripper interface.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewMonitor_HTTPClient(t *testing.T) {
+	called := false
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			called = true
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	m, err := NewMonitor(Config{
+		URL:                "https://example.com",
+		Method:             http.MethodGet,
+		HTTPClient:         client,
+		DontFollowRedirect: true,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	if client.CheckRedirect != nil || client.Timeout != 0 {
+		t.Errorf("NewMonitor() modified the supplied client")
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !called {
+		t.Errorf("Check() did not use the supplied client")
+	}
+	if !got.IsUp() {
+		t.Errorf("IsUp() = false, want true")
+	}
+}
+
+func TestNewMonitor_HTTPClientConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "Proxy", config: Config{Proxy: "http://proxy.example.com:3128"}},
+		{name: "DialIP", config: Config{DialIP: "127.0.0.1"}},
+		{name: "Network", config: Config{Network: "tcp4"}},
+		{name: "Resolver", config: Config{Resolver: &net.Resolver{}}},
+		{name: "DNSServer", config: Config{DNSServer: "127.0.0.1"}},
+		{name: "DNSTimeout", config: Config{DNSTimeout: time.Second}},
+		{name: "Client certificate", config: Config{ClientCertFile: "cert.pem", ClientKeyFile: "key.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.URL = "https://example.com"
+			tt.config.Method = http.MethodGet
+			tt.config.HTTPClient = &http.Client{}
+			_, err := NewMonitor(tt.config)
+			if err == nil || !strings.Contains(err.Error(), "HTTPClient") {
+				t.Errorf("NewMonitor() error = %v, want HTTPClient conflict", err)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckBasicAuth(t *testing.T) {
+	const user, pass = "admin", "secret"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != user || p != pass {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.Contains(r.URL.RawQuery, pass) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		user   string
+		pass   string
+		wantUp bool
+	}{
+		{name: "With credentials", user: user, pass: pass, wantUp: true},
+		{name: "Without credentials", wantUp: false},
+		{name: "Missing password", user: user, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:           ts.URL,
+				Method:        http.MethodGet,
+				BasicAuthUser: tt.user,
+				BasicAuthPass: tt.pass,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v", got.IsUp(), tt.wantUp)
+			}
+			if strings.Contains(got.String(), pass) {
+				t.Errorf("String() leaks the password")
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckTokenProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	errExpired := errors.New("refresh token expired")
+
+	tests := []struct {
+		name    string
+		tokens  []string
+		err     error
+		retries int
+		wantUp  bool
+		wantErr bool
+	}{
+		{name: "Fresh token per attempt", tokens: []string{"token-1", "token-2"}, retries: 1, wantUp: true},
+		{name: "Stale token", tokens: []string{"token-1"}, wantUp: false},
+		{name: "Provider error", err: errExpired, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			provider := func(ctx context.Context) (string, error) {
+				n := int(calls.Add(1))
+				if tt.err != nil {
+					return "", tt.err
+				}
+				return tt.tokens[min(n, len(tt.tokens))-1], nil
+			}
+
+			m, err := NewMonitor(Config{
+				URL:             ts.URL,
+				Method:          http.MethodGet,
+				Headers:         http.Header{"Authorization": {"Bearer static"}},
+				TokenProvider:   provider,
+				RetryCount:      tt.retries,
+				RetryDownStatus: true,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Check() error = %v, want %v", err, tt.err)
+				}
+				if got.IsUp() || got.Error == "" {
+					t.Errorf("result = %+v, want down result with Error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v", got.IsUp(), tt.wantUp)
+			}
+			if n := int(calls.Load()); n != tt.retries+1 {
+				t.Errorf("provider calls = %d, want %d", n, tt.retries+1)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckInvertResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(r.URL.Path[1:])
+		w.WriteHeader(code)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		status        int
+		upStatusCodes []int
+		invert        bool
+		wantUp        bool
+	}{
+		{name: "Forbidden is up when inverted", status: http.StatusForbidden, invert: true, wantUp: true},
+		{name: "OK is down when inverted", status: http.StatusOK, invert: true, wantUp: false},
+		{name: "Forbidden expected", status: http.StatusForbidden, upStatusCodes: []int{http.StatusForbidden}, wantUp: true},
+		{name: "Not found when forbidden expected", status: http.StatusNotFound, upStatusCodes: []int{http.StatusForbidden}, wantUp: false},
+		{name: "Inverted with status codes", status: http.StatusForbidden, upStatusCodes: []int{http.StatusForbidden}, invert: true, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:           ts.URL + "/" + strconv.Itoa(tt.status),
+				Method:        http.MethodGet,
+				UpStatusCodes: tt.upStatusCodes,
+				InvertResult:  tt.invert,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (%s)", got.IsUp(), tt.wantUp, got.Summary())
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckFinalURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle?"+r.URL.RawQuery, http.StatusFound)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/end", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		wantPath  string
+		wantCount int
+	}{
+		{name: "No redirect", path: "/end", wantPath: "/end", wantCount: 0},
+		{name: "Two redirects", path: "/start", wantPath: "/end", wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: ts.URL + tt.path, Method: http.MethodGet})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if want := ts.URL + tt.wantPath; got.FinalURL != want {
+				t.Errorf("FinalURL = %q, want %q", got.FinalURL, want)
+			}
+			if got.RedirectCount != tt.wantCount {
+				t.Errorf("RedirectCount = %d, want %d", got.RedirectCount, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckBodyContains(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>status: OK</html>"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		bodyContains string
+		maxBodyBytes int64
+		wantMatched  bool
+	}{
+		{name: "No expectation", wantMatched: true},
+		{name: "Match", bodyContains: "status: OK", wantMatched: true},
+		{name: "No match", bodyContains: "status: FAIL", wantMatched: false},
+		{name: "Match beyond limit", bodyContains: "status: OK", maxBodyBytes: 8, wantMatched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL,
+				Method:       http.MethodGet,
+				BodyContains: tt.bodyContains,
+				MaxBodyBytes: tt.maxBodyBytes,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.BodyMatched != tt.wantMatched {
+				t.Errorf("BodyMatched = %v, want %v", got.BodyMatched, tt.wantMatched)
+			}
+			if got.Up != tt.wantMatched {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckBodyTruncated(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/endless" {
+			// Stream until the client goes away.
+			chunk := []byte(strings.Repeat("x", 1024))
+			for r.Context().Err() == nil {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+			}
+			return
+		}
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		maxBodyBytes  int64
+		wantLength    int64
+		wantTruncated bool
+	}{
+		{name: "Within limit", path: "/", maxBodyBytes: 10, wantLength: 10},
+		{name: "Over limit", path: "/", maxBodyBytes: 4, wantLength: 4, wantTruncated: true},
+		{name: "Largest limit", path: "/", maxBodyBytes: math.MaxInt64, wantLength: 10},
+		{name: "Endless", path: "/endless", maxBodyBytes: 64 << 10, wantLength: 64 << 10, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL + tt.path,
+				Method:       http.MethodGet,
+				ReadBody:     true,
+				MaxBodyBytes: tt.maxBodyBytes,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.ContentLength != tt.wantLength || got.BodyTruncated != tt.wantTruncated {
+				t.Errorf("ContentLength = %d, BodyTruncated = %v, want %d and %v",
+					got.ContentLength, got.BodyTruncated, tt.wantLength, tt.wantTruncated)
+			}
+			if !got.IsUp() {
+				t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckMinBodyBytes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("status: OK"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		minBodyBytes int64
+		bodyContains string
+		wantUp       bool
+		wantReason   string
+	}{
+		{name: "Large enough", minBodyBytes: 10, wantUp: true},
+		{name: "Too small", minBodyBytes: 11, wantUp: false, wantReason: "body is 10 bytes, want at least 11"},
+		{name: "With BodyContains", minBodyBytes: 5, bodyContains: "OK", wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL,
+				Method:       http.MethodGet,
+				MinBodyBytes: tt.minBodyBytes,
+				BodyContains: tt.bodyContains,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantUp)
+			}
+			if got.ContentLength != 10 {
+				t.Errorf("ContentLength = %d, want 10", got.ContentLength)
+			}
+			if tt.wantReason != "" && !slices.Contains(got.Reasons(), tt.wantReason) {
+				t.Errorf("Reasons() = %v, want %q", got.Reasons(), tt.wantReason)
+			}
+		})
+	}
+
+	if _, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, MinBodyBytes: 10, MaxBodyBytes: 5}); err == nil {
+		t.Error("NewMonitor() with MinBodyBytes > MaxBodyBytes succeeded")
+	}
+}
+
+func TestMonitor_CheckBodySnippet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("all good"))
+	})
+	mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down for maintenance"))
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		capture      bool
+		snippetBytes int64
+		bodyContains string
+		want         string
+	}{
+		{name: "Failure captured", path: "/down", capture: true, want: "down for maintenance"},
+		{name: "Failure truncated", path: "/down", capture: true, snippetBytes: 4, want: "down"},
+		{name: "Failure with body read", path: "/down", capture: true, snippetBytes: 4, bodyContains: "maintenance", want: "down"},
+		{name: "Success not captured", path: "/ok", capture: true, want: ""},
+		{name: "Disabled", path: "/down", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                  ts.URL + tt.path,
+				Method:               http.MethodGet,
+				CaptureBodyOnFailure: tt.capture,
+				BodySnippetBytes:     tt.snippetBytes,
+				BodyContains:         tt.bodyContains,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.BodySnippet != tt.want {
+				t.Errorf("BodySnippet = %q, want %q", got.BodySnippet, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckUserAgent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		headers   http.Header
+		want      string
+	}{
+		{name: "Default", want: DefaultUserAgent},
+		{name: "Configured", userAgent: "probe/2.0", want: "probe/2.0"},
+		{
+			name:      "Header wins",
+			userAgent: "probe/2.0",
+			headers:   http.Header{"User-Agent": {"custom/3.0"}},
+			want:      "custom/3.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL,
+				Method:       http.MethodGet,
+				UserAgent:    tt.userAgent,
+				Headers:      tt.headers,
+				BodyContains: tt.want,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.BodyMatched {
+				t.Errorf("server did not receive User-Agent %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckAccept(t *testing.T) {
+	var gotAccept, gotEncoding string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		gotEncoding = r.Header.Get("Accept-Encoding")
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		config       Config
+		wantAccept   string
+		wantEncoding string
+	}{
+		{name: "Default", wantEncoding: "gzip"},
+		{
+			name:         "Configured",
+			config:       Config{Accept: "application/json", AcceptEncoding: "identity"},
+			wantAccept:   "application/json",
+			wantEncoding: "identity",
+		},
+		{
+			name: "Header wins",
+			config: Config{
+				Accept:  "application/json",
+				Headers: http.Header{"Accept": {"text/plain"}},
+			},
+			wantAccept:   "text/plain",
+			wantEncoding: "gzip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.URL = ts.URL
+			config.Method = http.MethodGet
+
+			m, err := NewMonitor(config)
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if gotAccept != tt.wantAccept {
+				t.Errorf("Accept = %q, want %q", gotAccept, tt.wantAccept)
+			}
+			if gotEncoding != tt.wantEncoding {
+				t.Errorf("Accept-Encoding = %q, want %q", gotEncoding, tt.wantEncoding)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckDisableCacheBusting(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("query=" + r.URL.RawQuery + " pragma=" + r.Header.Get("Pragma")))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		disable bool
+		want    string
+	}{
+		{name: "Enabled", disable: false, want: "pragma=no-cache"},
+		{name: "Disabled", disable: true, want: "query=sig=abc pragma="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                 ts.URL + "?sig=abc",
+				Method:              http.MethodGet,
+				DisableCacheBusting: tt.disable,
+				BodyContains:        tt.want,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.BodyMatched {
+				t.Errorf("server response did not contain %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckAcceptLanguage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Accept-Language"), "de") {
+			w.Write([]byte("<h1>Willkommen</h1>"))
+			return
+		}
+		w.Write([]byte("<h1>Welcome</h1>"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		language string
+		headers  http.Header
+		contains string
+		wantUp   bool
+	}{
+		{name: "Default", contains: "Welcome", wantUp: true},
+		{name: "Localized", language: "de-DE", contains: "Willkommen", wantUp: true},
+		{name: "Wrong language", language: "de-DE", contains: "Welcome"},
+		{name: "Header wins", language: "de-DE", headers: http.Header{"Accept-Language": {"en-US"}}, contains: "Welcome", wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            ts.URL,
+				Method:         http.MethodGet,
+				AcceptLanguage: tt.language,
+				Headers:        tt.headers,
+				BodyContains:   tt.contains,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckHeaders(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer ts.Close()
+
+	config := Config{URL: ts.URL, Method: http.MethodGet}.
+		WithHeader("X-Api-Key", "secret").
+		WithHeader("Cache-Control", "max-age=0")
+
+	m, err := NewMonitor(config)
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	if _, err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	want := map[string]string{
+		"X-Api-Key":     "secret",
+		"Cache-Control": "max-age=0",
+		"Pragma":        "no-cache",
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("server received %s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+}
+
+func TestMonitor_CheckHeaderPrecedence(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer ts.Close()
+
+	defaults := http.Header{
+		"Cache-Control": {"max-age=60"},
+		"User-Agent":    {"shared-agent"},
+		"X-Team":        {"platform"},
+	}
+
+	tests := []struct {
+		name     string
+		headers  http.Header
+		defaults http.Header
+		want     http.Header
+	}{
+		{
+			name: "Library defaults",
+			want: http.Header{
+				"Cache-Control": {"no-cache, no-store, must-revalidate"},
+				"Pragma":        {"no-cache"},
+				"Expires":       {"0"},
+				"User-Agent":    {DefaultUserAgent},
+			},
+		},
+		{
+			name:     "Shared defaults override library",
+			defaults: defaults,
+			want: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Pragma":        {"no-cache"},
+				"User-Agent":    {"shared-agent"},
+				"X-Team":        {"platform"},
+			},
+		},
+		{
+			name:     "Monitor headers override shared defaults",
+			headers:  http.Header{"cache-control": {"no-transform"}, "X-Team": {"web", "edge"}},
+			defaults: defaults,
+			want: http.Header{
+				"Cache-Control": {"no-transform"},
+				"Pragma":        {"no-cache"},
+				"User-Agent":    {"shared-agent"},
+				"X-Team":        {"web", "edge"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{URL: ts.URL, Method: http.MethodGet, Headers: tt.headers}
+			if tt.defaults != nil {
+				config = config.WithDefaultHeaders(tt.defaults)
+			}
+
+			m, err := NewMonitor(config)
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			for name, values := range tt.want {
+				if !slices.Equal(got.Values(name), values) {
+					t.Errorf("server received %s = %q, want %q", name, got.Values(name), values)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_WithDefaultHeaders(t *testing.T) {
+	base := Config{Headers: http.Header{"X-Env": {"prod"}}}
+	derived := base.WithDefaultHeaders(http.Header{"x-env": {"dev"}, "X-Region": {"us"}})
+
+	if got := derived.Headers.Get("X-Env"); got != "prod" {
+		t.Errorf("derived X-Env = %q, want %q", got, "prod")
+	}
+	if got := derived.Headers.Get("X-Region"); got != "us" {
+		t.Errorf("derived X-Region = %q, want %q", got, "us")
+	}
+	if _, ok := base.Headers["X-Region"]; ok {
+		t.Errorf("base Headers modified: %v", base.Headers)
+	}
+}
+
+func TestMonitor_CheckCookies(t *testing.T) {
+	var received string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Cookie")
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		}
+	}))
+	defer ts.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		jar     http.CookieJar
+		cookies []*http.Cookie
+		want    []string // Cookie header received by each check
+	}{
+		{name: "No cookies", want: []string{"", ""}},
+		{name: "Jar", jar: jar, want: []string{"", "session=abc", "session=abc"}},
+		{name: "Static", cookies: []*http.Cookie{{Name: "session", Value: "xyz"}}, want: []string{"session=xyz", "session=xyz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:       ts.URL,
+				Method:    http.MethodGet,
+				CookieJar: tt.jar,
+				Cookies:   tt.cookies,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			for i, want := range tt.want {
+				if _, err := m.Check(context.Background()); err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				if received != want {
+					t.Errorf("check %d: server received Cookie %q, want %q", i+1, received, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Clone(t *testing.T) {
+	base := Config{
+		URL:             "https://example.com",
+		Method:          http.MethodGet,
+		UpStatusCodes:   []int{200},
+		Headers:         http.Header{"X-Env": {"prod"}},
+		ExpectedHeaders: http.Header{"Server": {"nginx"}},
+		CaptureHeaders:  []string{"Server"},
+	}
+
+	clone := base.Clone()
+	clone.UpStatusCodes[0] = 204
+	clone.Headers.Set("X-Env", "staging")
+	clone.ExpectedHeaders.Set("Server", "caddy")
+	clone.CaptureHeaders[0] = "Date"
+
+	if base.UpStatusCodes[0] != 200 || base.Headers.Get("X-Env") != "prod" ||
+		base.ExpectedHeaders.Get("Server") != "nginx" || base.CaptureHeaders[0] != "Server" {
+		t.Errorf("modifying the clone changed the original: %+v", base)
+	}
+}
+
+func TestMonitor_WithURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + " " + r.Header.Get("X-Env")))
+	}))
+	defer ts.Close()
+
+	base, err := NewMonitor(Config{
+		URL:          ts.URL + "/a",
+		Method:       http.MethodGet,
+		Headers:      http.Header{"X-Env": {"prod"}},
+		BodyContains: "prod",
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	m, err := base.WithURL(ts.URL + "/b")
+	if err != nil {
+		t.Fatalf("WithURL() error = %v", err)
+	}
+	m.config.Headers.Set("X-Env", "staging")
+
+	if got := base.config.Headers.Get("X-Env"); got != "prod" {
+		t.Errorf("base X-Env = %q, want %q", got, "prod")
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got.URL != ts.URL+"/b" {
+		t.Errorf("URL = %q, want %q", got.URL, ts.URL+"/b")
+	}
+	if got.BodyMatched {
+		t.Errorf("BodyMatched = true, want the modified header to be sent")
+	}
+
+	if _, err := base.WithURL("not a url"); err == nil {
+		t.Error("WithURL() with an invalid URL succeeded")
+	}
+}
+
+func TestConfig_WithHeader(t *testing.T) {
+	base := Config{Headers: http.Header{"X-Env": {"prod"}}}
+	derived := base.WithHeader("X-Env", "staging")
+
+	if got := base.Headers.Get("X-Env"); got != "prod" {
+		t.Errorf("base X-Env = %q, want %q", got, "prod")
+	}
+	if got := derived.Headers.Get("X-Env"); got != "staging" {
+		t.Errorf("derived X-Env = %q, want %q", got, "staging")
+	}
+}
+
+func TestMonitor_CheckPreservesQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("region") != "us" || query.Get("tier") != "gold" || query.Get("nocache") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL + "/health?region=us&tier=gold", Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !got.IsUp() {
+		t.Errorf("StatusCode = %d, query parameters were not preserved", got.StatusCode)
+	}
+}
+
+func TestMonitor_CheckCacheBustParam(t *testing.T) {
+	var rawQuery string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new?"+r.URL.RawQuery, http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		param     string
+		disable   bool
+		wantParam string
+	}{
+		{name: "Default", wantParam: "nocache"},
+		{name: "Custom", param: "_cb", wantParam: "_cb"},
+		{name: "Disabled", param: "_cb", disable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                 ts.URL + "/old?b=2&a=1%2B",
+				Method:              http.MethodGet,
+				CacheBustParam:      tt.param,
+				DisableCacheBusting: tt.disable,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			others, value, _ := strings.Cut(rawQuery, "&"+tt.wantParam+"=")
+			if tt.wantParam == "" {
+				others = rawQuery
+			} else if value == "" {
+				t.Errorf("query = %q, want %s parameter appended", rawQuery, tt.wantParam)
+			}
+			if others != "b=2&a=1%2B" {
+				t.Errorf("query = %q, want other parameters unchanged", rawQuery)
+			}
+			if tt.wantParam != "nocache" && strings.Contains(rawQuery, "nocache") {
+				t.Errorf("query = %q, want no nocache parameter", rawQuery)
+			}
+			if want := ts.URL + "/new?b=2&a=1%2B"; got.FinalURL != want {
+				t.Errorf("FinalURL = %q, want %q", got.FinalURL, want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckDegraded(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		want      bool
+	}{
+		{name: "No threshold", threshold: 0, want: false},
+		{name: "Within threshold", threshold: time.Minute, want: false},
+		{name: "Exceeds threshold", threshold: time.Millisecond, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                   ts.URL,
+				Method:                http.MethodGet,
+				ResponseTimeThreshold: tt.threshold,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Degraded != tt.want {
+				t.Errorf("Degraded = %v, want %v", got.Degraded, tt.want)
+			}
+			if !got.IsUp() {
+				t.Errorf("IsUp() = false, want true")
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckTLSVersion(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		minVersion uint16
+		wantUp     bool
+	}{
+		{name: "No minimum", minVersion: 0, wantUp: true},
+		{name: "Meets minimum", minVersion: tls.VersionTLS12, wantUp: true},
+		{name: "Below minimum", minVersion: tls.VersionTLS13, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:           ts.URL,
+				Method:        http.MethodGet,
+				IgnoreCert:    true,
+				MinTLSVersion: tt.minVersion,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v", got.IsUp(), tt.wantUp)
+			}
+			if got.CertInfo.TLSVersion != "TLS 1.2" {
+				t.Errorf("TLSVersion = %q, want %q", got.CertInfo.TLSVersion, "TLS 1.2")
+			}
+			if got.CertInfo.CipherSuite == "" {
+				t.Errorf("CipherSuite is empty")
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckHTTP2(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	h2Server := httptest.NewUnstartedServer(handler)
+	h2Server.EnableHTTP2 = true
+	h2Server.StartTLS()
+	defer h2Server.Close()
+
+	h1Server := httptest.NewTLSServer(handler)
+	defer h1Server.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		wantProto string
+	}{
+		{name: "HTTP/2", url: h2Server.URL, wantProto: "HTTP/2.0"},
+		{name: "HTTP/1.1 fallback", url: h1Server.URL, wantProto: "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: tt.url, Method: http.MethodGet, IgnoreCert: true, ExpectHTTP2: true})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Proto != tt.wantProto {
+				t.Errorf("Proto = %q, want %q", got.Proto, tt.wantProto)
+			}
+			if wantUp := tt.wantProto == "HTTP/2.0"; got.Up != wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, wantUp)
+			}
+			if !strings.Contains(got.String(), "Protocol: "+tt.wantProto+"\n") {
+				t.Errorf("String() does not include the protocol:\n%s", got.String())
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.Header().Set("Via", "test-proxy")
+	}))
+	defer proxy.Close()
+
+	m, err := NewMonitor(Config{
+		URL:             "http://monitored.example.com/health",
+		Method:          http.MethodGet,
+		Proxy:           proxy.URL,
+		ExpectedHeaders: http.Header{"Via": {"test-proxy"}},
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !got.IsUp() {
+		t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+	}
+	if proxiedHost != "monitored.example.com" {
+		t.Errorf("proxy received host %q, want %q", proxiedHost, "monitored.example.com")
+	}
+}
+
+func TestMonitor_CheckProxyConnect(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// The proxy tunnels HTTPS requests with CONNECT, so TLS is negotiated
+	// with the site itself.
+	var tunneled atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tunneled.Add(1)
+		conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		go io.Copy(upstream, buf)
+		io.Copy(conn, upstream)
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		name       string
+		ignoreCert bool
+		wantErr    bool
+	}{
+		{name: "IgnoreCert", ignoreCert: true},
+		{name: "Untrusted certificate", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tunneled.Store(0)
+			m, err := NewMonitor(Config{
+				URL:        ts.URL,
+				Method:     http.MethodGet,
+				Proxy:      proxy.URL,
+				IgnoreCert: tt.ignoreCert,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tunneled.Load() == 0 {
+				t.Errorf("request was not tunneled through the proxy")
+			}
+			if tt.wantErr {
+				var checkErr *CheckError
+				if !errors.As(err, &checkErr) || checkErr.Phase != PhaseTLS {
+					t.Errorf("Check() error = %v, want TLS error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.IsUp() {
+				t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+			}
+		})
+	}
+}
+
+func TestNewMonitor_InvalidProxy(t *testing.T) {
+	_, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet, Proxy: "proxy:3128"})
+	if err == nil {
+		t.Errorf("NewMonitor() error = nil, want invalid proxy error")
+	}
+}
+
+func TestMonitor_CheckDialIP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Host, "backend.example.com:") {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+		}
+	}))
+	defer ts.Close()
+
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort() error = %v", err)
+	}
+
+	m, err := NewMonitor(Config{
+		URL:    "http://backend.example.com:" + port + "/health",
+		Method: http.MethodGet,
+		DialIP: "127.0.0.1",
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !got.IsUp() {
+		t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+	}
+}
+
+func TestMonitor_CheckDialIPRedirect(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+
+	_, otherPort, err := net.SplitHostPort(other.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort() error = %v", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+otherPort+"/", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort() error = %v", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		dialed []string
+	)
+	var dialer net.Dialer
+	m, err := NewMonitor(Config{
+		URL:    "http://backend.example.com:" + port + "/",
+		Method: http.MethodGet,
+		DialIP: "127.0.0.1",
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			mu.Lock()
+			dialed = append(dialed, addr)
+			mu.Unlock()
+			return dialer.DialContext(ctx, network, addr)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil || !got.IsUp() {
+		t.Fatalf("Check() = %v, %v, want up", got.Summary(), err)
+	}
+
+	want := []string{"127.0.0.1:" + port, "localhost:" + otherPort}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(dialed, want) {
+		t.Errorf("dialed %q, want %q", dialed, want)
+	}
+}
+
+func TestNewMonitor_InvalidDialIP(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "Not an IP", config: Config{DialIP: "not-an-ip"}},
+		{name: "With Proxy", config: Config{DialIP: "127.0.0.1", Proxy: "http://proxy.example.com:3128"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.URL = "https://example.com"
+			tt.config.Method = http.MethodGet
+			if _, err := NewMonitor(tt.config); err == nil {
+				t.Errorf("NewMonitor() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCertInfo_Chain(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leaf, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, ca, caKey)
+
+	info := certInfo(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}}, "leaf.example.com", nil)
+
+	if len(info.Chain) != 2 {
+		t.Fatalf("len(Chain) = %d, want 2", len(info.Chain))
+	}
+	if info.Chain[0].Subject != "CN=leaf.example.com" || info.Chain[0].Issuer != "CN=Test CA" {
+		t.Errorf("Chain[0] = %+v, want leaf issued by Test CA", info.Chain[0])
+	}
+	if info.Chain[1].Subject != "CN=Test CA" || !info.Chain[1].ValidTo.Equal(ca.NotAfter) {
+		t.Errorf("Chain[1] = %+v, want Test CA", info.Chain[1])
+	}
+	if info.Subject != info.Chain[0].Subject {
+		t.Errorf("Subject = %q, want leaf subject", info.Subject)
+	}
+}
+
+func TestMonitor_CheckSkipsBody(t *testing.T) {
+	const bodySize = 64 << 20
+
+	tests := []struct {
+		name         string
+		bodyContains string
+	}{
+		{name: "No body matching"},
+		{name: "Body matching", bodyContains: "never found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := make(chan int64, 1)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n, _ := io.Copy(w, io.LimitReader(zeroReader{}, bodySize))
+				written <- n
+			}))
+			defer ts.Close()
+
+			m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, BodyContains: tt.bodyContains})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if n := <-written; n == bodySize {
+				t.Errorf("server wrote the entire %d byte body", n)
+			}
+		})
+	}
+}
+
+// zeroReader is an io.Reader that returns an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestMonitor_CheckSuccessFunc(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ready", r.URL.Query().Get("ready"))
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ready"))
+	}))
+	defer ts.Close()
+
+	ready := func(resp *http.Response) bool {
+		body, err := io.ReadAll(resp.Body)
+		return err == nil && string(body) == "ready" && resp.Header.Get("X-Ready") == "yes"
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "Accepted", query: "?ready=yes", want: true},
+		{name: "Rejected", query: "?ready=no", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: ts.URL + tt.query, Method: http.MethodGet, SuccessFunc: ready})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.want {
+				t.Errorf("IsUp() = %v, want %v", got.IsUp(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckOnResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: r.URL.Query().Get("session")})
+		w.Write([]byte("welcome"))
+	}))
+	defer ts.Close()
+
+	hasSession := func(resp *http.Response) error {
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == "session" && cookie.Value != "" {
+				return nil
+			}
+		}
+		return errors.New("no session cookie")
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantUp     bool
+		wantReason string
+	}{
+		{name: "Accepted", query: "?session=abc", wantUp: true},
+		{name: "Rejected", wantUp: false, wantReason: "response rejected by OnResponse: no session cookie"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL + tt.query,
+				Method:       http.MethodGet,
+				OnResponse:   hasSession,
+				BodyContains: "welcome",
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantUp)
+			}
+			if !got.BodyMatched {
+				t.Errorf("BodyMatched = false, want the body left unread by the hook")
+			}
+			if tt.wantReason != "" && !slices.Contains(got.Reasons(), tt.wantReason) {
+				t.Errorf("Reasons() = %v, want %q", got.Reasons(), tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckContent(t *testing.T) {
+	const body = "<html>hello</html>"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := body
+		if r.URL.Query().Get("long") != "" {
+			page += strings.Repeat(" ", drainBytes)
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Query().Get("chunked") != "" {
+			// Flushing before writing forces a chunked response.
+			w.(http.Flusher).Flush()
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+		}
+		w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	long := int64(len(body) + drainBytes)
+
+	tests := []struct {
+		name         string
+		query        string
+		readBody     bool
+		wantLength   int64
+		wantDeclared int64
+	}{
+		{name: "Declared length", wantLength: int64(len(body)), wantDeclared: int64(len(body))},
+		{name: "Chunked", query: "?chunked=1", wantLength: int64(len(body)), wantDeclared: -1},
+		{name: "Chunked long unread", query: "?chunked=1&long=1", wantLength: -1, wantDeclared: -1},
+		{name: "Chunked long read", query: "?chunked=1&long=1", readBody: true, wantLength: long, wantDeclared: -1},
+		{name: "Declared long unread", query: "?long=1", wantLength: -1, wantDeclared: long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: ts.URL + tt.query, Method: http.MethodGet, ReadBody: tt.readBody})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.ContentType != "text/html" {
+				t.Errorf("ContentType = %q, want %q", got.ContentType, "text/html")
+			}
+			if got.ContentLength != tt.wantLength || got.DeclaredLength != tt.wantDeclared {
+				t.Errorf("ContentLength = %d, DeclaredLength = %d, want %d and %d",
+					got.ContentLength, got.DeclaredLength, tt.wantLength, tt.wantDeclared)
+			}
+		})
+	}
+}
+
+func TestMonitor_Close(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     *http.Client
+		wantClosed bool
+	}{
+		{name: "Owned client", wantClosed: true},
+		{name: "Shared client", client: &http.Client{Transport: &http.Transport{}}, wantClosed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			closed := make(chan struct{}, 1)
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateClosed {
+					closed <- struct{}{}
+				}
+			}
+			ts.Start()
+			defer ts.Close()
+
+			m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, ReadBody: true, HTTPClient: tt.client})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if err := m.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			select {
+			case <-closed:
+				if !tt.wantClosed {
+					t.Error("Close() closed a connection of a shared client")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantClosed {
+					t.Error("Close() did not close the idle connection")
+				}
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckConcurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Team") != "web" || r.Header.Get(CheckIDHeader) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		fmt.Fprintf(w, "check %s", r.Header.Get(CheckIDHeader))
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{
+		URL:          ts.URL,
+		Method:       http.MethodGet,
+		Headers:      http.Header{"X-Team": {"web"}},
+		Cookies:      []*http.Cookie{{Name: "session", Value: "abc"}},
+		BodyContains: "check ",
+		SendCheckID:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+	defer m.Close()
+
+	const checks = 50
+	results := make([]*CheckResult, checks)
+	errs := make([]error, checks)
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = m.Check(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	ids := make(map[string]bool)
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("Check() error = %v", errs[i])
+		}
+		if !result.IsUp() {
+			t.Errorf("IsUp() = false, reasons %q", result.Reasons())
+		}
+		if ids[result.CheckID] {
+			t.Errorf("CheckID %q repeated", result.CheckID)
+		}
+		ids[result.CheckID] = true
+	}
+}
+
+func TestMonitor_CheckIdleConnTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	tests := []struct {
+		name            string
+		idleConnTimeout time.Duration
+		wantReused      bool
+	}{
+		{name: "Default", wantReused: true},
+		{name: "Idle timeout", idleConnTimeout: 20 * time.Millisecond, wantReused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                 ts.URL,
+				Method:              http.MethodGet,
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 1,
+				IdleConnTimeout:     tt.idleConnTimeout,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+			defer m.Close()
+
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			time.Sleep(100 * time.Millisecond)
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.ConnectionReused != tt.wantReused {
+				t.Errorf("ConnectionReused = %v, want %v", got.ConnectionReused, tt.wantReused)
+			}
+		})
+	}
+
+	for _, config := range []Config{{MaxIdleConns: -1}, {MaxIdleConnsPerHost: -1}, {IdleConnTimeout: -time.Second}} {
+		config.URL = ts.URL
+		config.Method = http.MethodGet
+		if _, err := NewMonitor(config); err == nil {
+			t.Errorf("NewMonitor(%+v) error = nil, want error", config)
+		}
+	}
+}
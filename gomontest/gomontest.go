@@ -0,0 +1,142 @@
+// Package gomontest provides HTTP test servers for testing code that uses
+// gomon, without depending on sites on the network.
+//
+// Each function starts an httptest.Server that is closed when the test
+// finishes. Servers with certificates listen on 127.0.0.1, and their
+// certificates are valid for 127.0.0.1 and localhost. Use CertPool to
+// trust one of them, for example to see an expired certificate reported
+// as expired instead of untrusted.
+package gomontest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// NewStatusServer starts an HTTP server that responds to every request
+// with the status code.
+func NewStatusServer(t testing.TB, code int) *httptest.Server {
+	return newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}))
+}
+
+// NewDelayServer starts an HTTP server that waits for delay before
+// responding with 200 OK. It stops waiting if the client goes away.
+func NewDelayServer(t testing.TB, delay time.Duration) *httptest.Server {
+	return newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+	}))
+}
+
+// NewRedirectServer starts an HTTP server that redirects count times,
+// with 302 Found, before responding with 200 OK. Each request path counts
+// the redirects left, so checks of the server's URL are independent.
+func NewRedirectServer(t testing.TB, count int) *httptest.Server {
+	return newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		left := count
+		if r.URL.Path != "/" {
+			n, err := strconv.Atoi(r.URL.Path[1:])
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			left = n
+		}
+
+		if left > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/%d", left-1), http.StatusFound)
+		}
+	}))
+}
+
+// NewSelfSignedServer starts an HTTPS server, responding with 200 OK, with
+// a valid self-signed certificate.
+func NewSelfSignedServer(t testing.TB) *httptest.Server {
+	return newTLSServer(t, time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour))
+}
+
+// NewExpiredServer starts an HTTPS server, responding with 200 OK, with a
+// self-signed certificate that expired a day ago.
+func NewExpiredServer(t testing.TB) *httptest.Server {
+	return newTLSServer(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+}
+
+// CertPool returns a pool holding the certificate of ts, for use as
+// gomon.Config.RootCAs.
+func CertPool(ts *httptest.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	return pool
+}
+
+// newServer starts an HTTP server with handler and closes it when the test
+// finishes.
+func newServer(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newTLSServer starts an HTTPS server, responding with 200 OK, with a
+// self-signed certificate valid between notBefore and notAfter.
+func newTLSServer(t testing.TB, notBefore, notAfter time.Time) *httptest.Server {
+	t.Helper()
+
+	cert, err := selfSignedCert(notBefore, notAfter)
+	if err != nil {
+		t.Fatalf("gomontest: %v", err)
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// selfSignedCert creates a self-signed certificate for 127.0.0.1 and
+// localhost.
+func selfSignedCert(notBefore, notAfter time.Time) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gomontest"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,90 @@
+package gomontest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bnixon67/gomon"
+)
+
+func TestServers(t *testing.T) {
+	selfSigned := NewSelfSignedServer(t)
+	expired := NewExpiredServer(t)
+
+	tests := []struct {
+		name   string
+		config gomon.Config
+		check  func(t *testing.T, result *gomon.CheckResult, err error)
+	}{
+		{
+			name:   "Status",
+			config: gomon.Config{URL: NewStatusServer(t, http.StatusServiceUnavailable).URL},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if result.StatusCode != http.StatusServiceUnavailable || result.IsUp() {
+					t.Errorf("StatusCode = %d, IsUp() = %v, want 503 and down", result.StatusCode, result.IsUp())
+				}
+			},
+		},
+		{
+			name:   "Delay",
+			config: gomon.Config{URL: NewDelayServer(t, time.Second).URL, RequestTimeout: 50 * time.Millisecond},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if !errors.Is(err, gomon.ErrAttemptTimeout) {
+					t.Errorf("Check() error = %v, want timeout", err)
+				}
+			},
+		},
+		{
+			name:   "Redirects",
+			config: gomon.Config{URL: NewRedirectServer(t, 3).URL},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if result.RedirectCount != 3 || !result.IsUp() {
+					t.Errorf("RedirectCount = %d, IsUp() = %v, want 3 and up", result.RedirectCount, result.IsUp())
+				}
+			},
+		},
+		{
+			name:   "Self-signed",
+			config: gomon.Config{URL: selfSigned.URL, IgnoreCert: true},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if result.CertInfo == nil || !result.CertInfo.SelfSigned || result.CertInfo.IsValid {
+					t.Errorf("CertInfo = %+v, want untrusted self-signed", result.CertInfo)
+				}
+			},
+		},
+		{
+			name:   "Self-signed trusted",
+			config: gomon.Config{URL: selfSigned.URL, RootCAs: CertPool(selfSigned)},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if result.CertInfo == nil || !result.CertInfo.IsValid {
+					t.Errorf("CertInfo = %+v, want valid", result.CertInfo)
+				}
+			},
+		},
+		{
+			name:   "Expired",
+			config: gomon.Config{URL: expired.URL, IgnoreCert: true, RootCAs: CertPool(expired)},
+			check: func(t *testing.T, result *gomon.CheckResult, err error) {
+				if result.CertInfo == nil || result.CertInfo.ErrorCode != gomon.CertErrorExpired {
+					t.Errorf("CertInfo = %+v, want expired", result.CertInfo)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Method = http.MethodGet
+			m, err := gomon.NewMonitor(tt.config)
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			result, err := m.Check(context.Background())
+			tt.check(t, result, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Group checks several monitors that together represent one service.
+type Group struct {
+	monitors []*Monitor
+	quorum   int
+}
+
+// GroupResult is the aggregate outcome of checking a Group. Results and
+// Errors hold the outcome of each monitor, in the order given to NewGroup.
+type GroupResult struct {
+	Up      bool           `json:"up"`
+	UpCount int            `json:"up_count"`
+	Quorum  int            `json:"quorum"`
+	Results []*CheckResult `json:"results"`
+	Errors  []error        `json:"-"`
+}
+
+// NewGroup returns a Group that is up when at least quorum of monitors are
+// up. A quorum of 0 requires all of them.
+func NewGroup(monitors []*Monitor, quorum int) (*Group, error) {
+	if len(monitors) == 0 {
+		return nil, errors.New("no monitors")
+	}
+
+	for i, m := range monitors {
+		if m == nil {
+			return nil, fmt.Errorf("nil monitor at index %d", i)
+		}
+	}
+
+	if quorum == 0 {
+		quorum = len(monitors)
+	}
+
+	if quorum < 0 || quorum > len(monitors) {
+		return nil, fmt.Errorf("quorum %d out of range for %d monitors", quorum, len(monitors))
+	}
+
+	return &Group{monitors: monitors, quorum: quorum}, nil
+}
+
+// Check checks every monitor in the group concurrently. A monitor whose
+// check returns an error counts as down.
+func (g *Group) Check(ctx context.Context) *GroupResult {
+	results, errs := CheckAll(ctx, g.monitors, len(g.monitors))
+
+	upCount := 0
+	for _, result := range results {
+		if result.IsUp() {
+			upCount++
+		}
+	}
+
+	return &GroupResult{
+		Up:      upCount >= g.quorum,
+		UpCount: upCount,
+		Quorum:  g.quorum,
+		Results: results,
+		Errors:  errs,
+	}
+}
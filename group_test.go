@@ -0,0 +1,89 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroup_Check(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	var monitors []*Monitor
+	for _, url := range []string{up.URL, up.URL + "/api", down.URL, closed.URL} {
+		m, err := NewMonitor(Config{URL: url, Method: http.MethodGet})
+		if err != nil {
+			t.Fatalf("NewMonitor() error = %v", err)
+		}
+		monitors = append(monitors, m)
+	}
+
+	tests := []struct {
+		name   string
+		quorum int
+		wantUp bool
+	}{
+		{name: "All required", quorum: 0, wantUp: false},
+		{name: "Quorum met", quorum: 2, wantUp: true},
+		{name: "Quorum not met", quorum: 3, wantUp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGroup(monitors, tt.quorum)
+			if err != nil {
+				t.Fatalf("NewGroup() error = %v", err)
+			}
+
+			got := g.Check(context.Background())
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantUp)
+			}
+			if got.UpCount != 2 {
+				t.Errorf("UpCount = %d, want 2", got.UpCount)
+			}
+			if len(got.Results) != len(monitors) || got.Results[2].StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("Results = %v, want one per monitor in order", got.Results)
+			}
+			if got.Errors[3] == nil {
+				t.Errorf("Errors[3] = nil, want connection error")
+			}
+		})
+	}
+}
+
+func TestNewGroup_Invalid(t *testing.T) {
+	m, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		monitors []*Monitor
+		quorum   int
+	}{
+		{name: "No monitors"},
+		{name: "Nil monitor", monitors: []*Monitor{m, nil}},
+		{name: "Quorum too large", monitors: []*Monitor{m}, quorum: 2},
+		{name: "Negative quorum", monitors: []*Monitor{m}, quorum: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGroup(tt.monitors, tt.quorum); err == nil {
+				t.Errorf("NewGroup() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package gomon
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HeaderMismatch describes an expected response header that was not found.
+type HeaderMismatch struct {
+	Name     string   `json:"name"`
+	Expected string   `json:"expected"`
+	Actual   []string `json:"actual"`
+}
+
+// String returns a description of the mismatch.
+func (hm HeaderMismatch) String() string {
+	if len(hm.Actual) == 0 {
+		return fmt.Sprintf("%s: missing", hm.Name)
+	}
+
+	return fmt.Sprintf("%s: expected %q, got %q", hm.Name, hm.Expected, strings.Join(hm.Actual, ", "))
+}
+
+// matchHeaders compares the expected headers against actual. Header names
+// are case-insensitive. An expected header with no values, or only an empty
+// value, only needs to be present. Otherwise each expected value must match
+// one of the actual values.
+func matchHeaders(expected, actual http.Header) []HeaderMismatch {
+	var mismatches []HeaderMismatch
+
+	for name, values := range expected {
+		name = http.CanonicalHeaderKey(name)
+		got := actual.Values(name)
+
+		if len(values) == 0 {
+			values = []string{""}
+		}
+
+		for _, want := range values {
+			if want == "" && len(got) > 0 {
+				continue
+			}
+			if want != "" && slices.Contains(got, want) {
+				continue
+			}
+
+			mismatches = append(mismatches, HeaderMismatch{Name: name, Expected: want, Actual: got})
+		}
+	}
+
+	slices.SortFunc(mismatches, func(a, b HeaderMismatch) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return mismatches
+}
+
+// DefaultSecurityHeaders are common response headers that harden a site
+// in browsers, for use as Config.SecurityHeaders.
+var DefaultSecurityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Content-Type-Options",
+	"X-Frame-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+}
+
+// SecurityHeaders reports which of the security headers named by
+// Config.SecurityHeaders a response included.
+type SecurityHeaders struct {
+	Present []string `json:"present,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// checkSecurityHeaders reports which of the headers named by names are in
+// actual, with the names in canonical form.
+func checkSecurityHeaders(names []string, actual http.Header) *SecurityHeaders {
+	var sh SecurityHeaders
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		if len(actual.Values(name)) > 0 {
+			sh.Present = append(sh.Present, name)
+		} else {
+			sh.Missing = append(sh.Missing, name)
+		}
+	}
+
+	return &sh
+}
+
+// receivedTrailers returns a copy of the trailers in t that have values,
+// or nil if there are none. Trailers announced by the server are listed in
+// t without values until the body has been read to the end.
+func receivedTrailers(t http.Header) http.Header {
+	var received http.Header
+	for name, values := range t {
+		if len(values) == 0 {
+			continue
+		}
+
+		if received == nil {
+			received = make(http.Header)
+		}
+		received[name] = slices.Clone(values)
+	}
+
+	return received
+}
+
+// DefaultCaptureHeaders are the response headers recorded in
+// CheckResult.Headers when Config.CaptureHeaders is nil.
+var DefaultCaptureHeaders = []string{"Cache-Control", "Date", "ETag", "Last-Modified", "Location", "Server"}
+
+// captureHeaders returns a copy of the headers in actual named by names, or
+// of all headers if names contains "*". It returns nil if none are present.
+func captureHeaders(names []string, actual http.Header) http.Header {
+	if slices.Contains(names, "*") {
+		return actual.Clone()
+	}
+
+	var captured http.Header
+	for _, name := range names {
+		values := actual.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if captured == nil {
+			captured = make(http.Header)
+		}
+		captured[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+
+	return captured
+}
+
+// clockSkew returns the difference between the Date header and received,
+// the local time the response arrived, or nil if the header is missing or
+// invalid. As Date has a resolution of one second, received is truncated
+// to the second, so that synchronized clocks usually show no skew.
+func clockSkew(header http.Header, received time.Time) *time.Duration {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return nil
+	}
+
+	skew := date.Sub(received.Truncate(time.Second))
+	return &skew
+}
@@ -0,0 +1,259 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchHeaders(t *testing.T) {
+	actual := http.Header{
+		"X-Cache":       {"HIT"},
+		"Cache-Control": {"public", "max-age=60"},
+	}
+
+	tests := []struct {
+		name     string
+		expected http.Header
+		want     []HeaderMismatch
+	}{
+		{name: "No expectations", expected: nil, want: nil},
+		{name: "Value match", expected: http.Header{"X-Cache": {"HIT"}}, want: nil},
+		{name: "Case-insensitive name", expected: http.Header{"x-cache": {"HIT"}}, want: nil},
+		{name: "One of many values", expected: http.Header{"Cache-Control": {"max-age=60"}}, want: nil},
+		{name: "Present", expected: http.Header{"X-Cache": {""}}, want: nil},
+		{name: "Present without values", expected: http.Header{"X-Cache": nil}, want: nil},
+		{
+			name:     "Value mismatch",
+			expected: http.Header{"X-Cache": {"MISS"}},
+			want:     []HeaderMismatch{{Name: "X-Cache", Expected: "MISS", Actual: []string{"HIT"}}},
+		},
+		{
+			name:     "Missing",
+			expected: http.Header{"X-Served-By": {""}},
+			want:     []HeaderMismatch{{Name: "X-Served-By"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchHeaders(tt.expected, actual)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureHeaders(t *testing.T) {
+	actual := http.Header{
+		"Server":   {"nginx"},
+		"Location": {"/next"},
+		"X-Trace":  {"abc"},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  http.Header
+	}{
+		{name: "Selected", names: []string{"server", "X-Missing"}, want: http.Header{"Server": {"nginx"}}},
+		{name: "All", names: []string{"*"}, want: actual},
+		{name: "None present", names: []string{"X-Missing"}, want: nil},
+		{name: "Empty list", names: []string{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := captureHeaders(tt.names, actual); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("captureHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckCapturesHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test-server")
+		w.Header().Set("X-Trace", "abc")
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if server := got.Headers.Get("Server"); server != "test-server" {
+		t.Errorf("Headers[Server] = %q, want %q", server, "test-server")
+	}
+	if trace := got.Headers.Get("X-Trace"); trace != "" {
+		t.Errorf("Headers[X-Trace] = %q, want it not captured by default", trace)
+	}
+	if !strings.Contains(got.String(), "Headers:\n  Date: ") {
+		t.Errorf("String() does not include captured headers:\n%s", got.String())
+	}
+}
+
+func TestMonitor_CheckTrailers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Write([]byte(strings.Repeat("x", 64)))
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		expected http.Header
+		maxBody  int64
+		readBody bool
+		wantUp   bool
+		wantSeen bool
+	}{
+		{name: "Not read", wantUp: true},
+		{name: "Read body", readBody: true, wantUp: true, wantSeen: true},
+		{name: "Expected", expected: http.Header{"Grpc-Status": {"0"}}, wantUp: true, wantSeen: true},
+		{name: "Expected past MaxBodyBytes", expected: http.Header{"Grpc-Status": {""}}, maxBody: 8},
+		{name: "Mismatch", expected: http.Header{"Grpc-Status": {"2"}}, wantSeen: true},
+		{name: "Missing", expected: http.Header{"Grpc-Message": {""}}, wantSeen: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:              ts.URL,
+				Method:           http.MethodGet,
+				ExpectedTrailers: tt.expected,
+				MaxBodyBytes:     tt.maxBody,
+				ReadBody:         tt.readBody,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+			if seen := got.Trailers.Get("Grpc-Status") == "0"; seen != tt.wantSeen {
+				t.Errorf("Trailers = %v, want Grpc-Status recorded %v", got.Trailers, tt.wantSeen)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckClockSkew(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ahead":
+			w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		case "/invalid":
+			w.Header().Set("Date", "yesterday")
+		case "/missing":
+			w.Header()["Date"] = nil // suppress the Date header the server adds
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		wantKnown bool
+		wantSkew  time.Duration // to within the one-second resolution of Date
+		wantUp    bool
+	}{
+		{name: "In sync", path: "/", wantKnown: true, wantUp: true},
+		{name: "Ahead", path: "/ahead", wantKnown: true, wantSkew: time.Hour},
+		{name: "Invalid", path: "/invalid", wantUp: true},
+		{name: "Missing", path: "/missing", wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: ts.URL + tt.path, Method: http.MethodGet, MaxClockSkew: time.Minute})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			switch {
+			case (got.ClockSkew != nil) != tt.wantKnown:
+				t.Errorf("ClockSkew = %v, want known %v", got.ClockSkew, tt.wantKnown)
+			case tt.wantKnown && (*got.ClockSkew-tt.wantSkew).Abs() > time.Second:
+				t.Errorf("ClockSkew = %v, want about %v", *got.ClockSkew, tt.wantSkew)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckSecurityHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=63072000")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		required    []string
+		wantMissing []string
+	}{
+		{name: "Not checked"},
+		{name: "Present", required: []string{"strict-transport-security", "X-Content-Type-Options"}},
+		{name: "Defaults", required: DefaultSecurityHeaders, wantMissing: []string{
+			"Content-Security-Policy", "X-Frame-Options", "Referrer-Policy", "Permissions-Policy",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, SecurityHeaders: tt.required})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if tt.required == nil {
+				if got.SecurityHeaders != nil {
+					t.Errorf("SecurityHeaders = %+v, want nil", got.SecurityHeaders)
+				}
+				return
+			}
+
+			wantPresent := []string{"Strict-Transport-Security", "X-Content-Type-Options"}
+			if !reflect.DeepEqual(got.SecurityHeaders.Present, wantPresent) {
+				t.Errorf("Present = %q, want %q", got.SecurityHeaders.Present, wantPresent)
+			}
+			if !reflect.DeepEqual(got.SecurityHeaders.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %q, want %q", got.SecurityHeaders.Missing, tt.wantMissing)
+			}
+			if wantUp := len(tt.wantMissing) == 0; got.IsUp() != wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), wantUp, got.Reasons())
+			}
+		})
+	}
+}
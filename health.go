@@ -0,0 +1,51 @@
+package gomon
+
+import "fmt"
+
+// fail marks the site as down and records the reason.
+func (result *CheckResult) fail(format string, args ...any) {
+	result.Up = false
+	result.failures = append(result.failures, fmt.Sprintf(format, args...))
+}
+
+// failed records err, which ended the attempt, in result and returns both,
+// so that what is known about a failed attempt is kept.
+func (result *CheckResult) failed(err error) (*CheckResult, error) {
+	result.Err = err
+	result.Error = err.Error()
+	result.ErrorCode = classifyFailure(err)
+	result.fail("%v", err)
+	return result, err
+}
+
+// Healthy reports whether the check met every configured expectation: the
+// site is up, the response was not degraded, and any certificate is valid.
+// It is safe to call on a nil result.
+func (result *CheckResult) Healthy() bool {
+	return result != nil && len(result.Reasons()) == 0
+}
+
+// Reasons describes why the check is not healthy. It returns nil for a
+// healthy result.
+func (result *CheckResult) Reasons() []string {
+	if result == nil {
+		return []string{"no result"}
+	}
+
+	var reasons []string
+
+	reasons = append(reasons, result.failures...)
+	if !result.Up && len(result.failures) == 0 {
+		reasons = append(reasons, "site is down")
+	}
+
+	if result.Degraded {
+		reasons = append(reasons, fmt.Sprintf("slow response: %s", result.End.Sub(result.Start)))
+	}
+
+	if result.CertInfo != nil && !result.CertInfo.IsValid {
+		reasons = append(reasons, fmt.Sprintf("invalid certificate: %s", result.CertInfo.ErrorMsg))
+	}
+
+	return reasons
+}
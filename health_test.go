@@ -0,0 +1,62 @@
+package gomon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckResult_Healthy(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name        string
+		result      *CheckResult
+		wantHealthy bool
+		wantReasons []string
+	}{
+		{
+			name:        "Healthy",
+			result:      &CheckResult{Up: true, CertInfo: &CertInfo{IsValid: true}},
+			wantHealthy: true,
+		},
+		{
+			name:        "Nil result",
+			result:      nil,
+			wantHealthy: false,
+			wantReasons: []string{"no result"},
+		},
+		{
+			name:        "Down without recorded failure",
+			result:      &CheckResult{},
+			wantReasons: []string{"site is down"},
+		},
+		{
+			name: "Several problems",
+			result: &CheckResult{
+				Up:       false,
+				Degraded: true,
+				Start:    start,
+				End:      start.Add(2 * time.Second),
+				CertInfo: &CertInfo{IsValid: false, ErrorMsg: "expired"},
+				failures: []string{"unexpected status code 503"},
+			},
+			wantReasons: []string{
+				"unexpected status code 503",
+				"slow response: 2s",
+				"invalid certificate: expired",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Healthy(); got != tt.wantHealthy {
+				t.Errorf("Healthy() = %v, want %v", got, tt.wantHealthy)
+			}
+			if got := tt.result.Reasons(); !reflect.DeepEqual(got, tt.wantReasons) {
+				t.Errorf("Reasons() = %q, want %q", got, tt.wantReasons)
+			}
+		})
+	}
+}
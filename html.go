@@ -0,0 +1,83 @@
+package gomon
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// htmlTable lays out the results for WriteHTML.
+var htmlTable = template.Must(template.New("table").Parse(`<table class="gomon">
+<thead><tr><th>URL</th><th>State</th><th>Status</th><th>Latency</th><th>Certificate Expires</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr class="{{.Class}}">
+<td><a href="{{.URL}}">{{.URL}}</a></td>
+<td style="color:{{.Color}}" title="{{.Reasons}}">{{.State}}</td>
+<td>{{if .StatusCode}}{{.StatusCode}}{{end}}</td>
+<td>{{.Latency}}</td>
+<td>{{.CertExpiry}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+`))
+
+// htmlRow is one row of the table written by WriteHTML.
+type htmlRow struct {
+	URL        string
+	State      string
+	Class      string
+	Color      template.CSS
+	Reasons    string
+	StatusCode int
+	Latency    time.Duration
+	CertExpiry string
+}
+
+// WriteHTML writes the results to w as an HTML table, one row per result,
+// showing the URL, whether the site is up, the status code, the latency
+// and any certificate expiry. The state is colored, and the reasons a site
+// is not healthy appear as a tooltip. The table has class "gomon", and each
+// row the class "up", "degraded" or "down", for styling. Nil results are
+// skipped. All values are escaped, so the fragment can be embedded in a
+// status page.
+func WriteHTML(w io.Writer, results []*CheckResult) error {
+	var rows []htmlRow
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		row := htmlRow{
+			URL:        result.URL,
+			State:      "UP",
+			Class:      "up",
+			Color:      "green",
+			Reasons:    strings.Join(result.Reasons(), "; "),
+			StatusCode: result.StatusCode,
+			Latency:    result.End.Sub(result.Start).Round(time.Millisecond),
+		}
+		switch {
+		case !result.Up:
+			row.State, row.Class, row.Color = "DOWN", "down", "red"
+		case !result.Healthy():
+			row.State, row.Class, row.Color = "DEGRADED", "degraded", "darkorange"
+		}
+
+		if cert := result.CertInfo; cert != nil {
+			row.CertExpiry = cert.ValidTo.Format(time.DateOnly)
+			if days := cert.DaysUntilExpiry(); days >= 0 {
+				row.CertExpiry += " (in " + strconv.Itoa(days) + " days)"
+			} else {
+				row.CertExpiry += " (expired " + strconv.Itoa(-days) + " days ago)"
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return htmlTable.Execute(w, rows)
+}
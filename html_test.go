@@ -0,0 +1,49 @@
+package gomon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHTML(t *testing.T) {
+	start := time.Now()
+	results := []*CheckResult{
+		{
+			URL: "https://example.com/?q=<script>", StatusCode: 200, Up: true, Start: start, End: start.Add(143 * time.Millisecond),
+			CertInfo: &CertInfo{IsValid: true, ValidTo: start.Add(30*24*time.Hour + time.Hour)},
+		},
+		{URL: "https://slow.example.com", StatusCode: 200, Up: true, Degraded: true, Start: start, End: start},
+		nil,
+	}
+	down, _ := (&CheckResult{URL: "https://down.example.com"}).failed(errors.New(`dial "tcp": refused`))
+	results = append(results, down)
+
+	var builder strings.Builder
+	if err := WriteHTML(&builder, results); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	got := builder.String()
+
+	for _, want := range []string{
+		`<table class="gomon">`,
+		`<td><a href="https://example.com/?q=%3cscript%3e">https://example.com/?q=&lt;script&gt;</a></td>`,
+		`<td style="color:green" title="">UP</td>`,
+		`<td>143ms</td>`,
+		`(in 30 days)`,
+		`<tr class="degraded">`,
+		`<tr class="down">`,
+		`title="dial &#34;tcp&#34;: refused">DOWN</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteHTML() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("WriteHTML() did not escape the URL:\n%s", got)
+	}
+	if rows := strings.Count(got, "<tr class="); rows != 3 {
+		t.Errorf("WriteHTML() wrote %d rows, want 3", rows)
+	}
+}
@@ -0,0 +1,70 @@
+package gomon
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MarshalJSON encodes the result with times in RFC 3339 format, the total
+// duration and any clock skew in milliseconds, and the overall health
+// verdict.
+func (result *CheckResult) MarshalJSON() ([]byte, error) {
+	type plainResult CheckResult
+
+	var clockSkewMS *float64
+	if result.ClockSkew != nil {
+		ms := milliseconds(*result.ClockSkew)
+		clockSkewMS = &ms
+	}
+
+	return json.Marshal(struct {
+		*plainResult
+		DurationMS  float64  `json:"duration_ms"`
+		ClockSkewMS *float64 `json:"clock_skew_ms,omitempty"`
+		Healthy     bool     `json:"healthy"`
+		Reasons     []string `json:"reasons,omitempty"`
+	}{
+		plainResult: (*plainResult)(result),
+		DurationMS:  milliseconds(result.End.Sub(result.Start)),
+		ClockSkewMS: clockSkewMS,
+		Healthy:     result.Healthy(),
+		Reasons:     result.Reasons(),
+	})
+}
+
+// MarshalJSON encodes the timings in milliseconds.
+func (t Timings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		DNSMS          float64 `json:"dns_ms"`
+		ConnectMS      float64 `json:"connect_ms"`
+		TLSHandshakeMS float64 `json:"tls_handshake_ms"`
+		FirstByteMS    float64 `json:"first_byte_ms"`
+	}{
+		DNSMS:          milliseconds(t.DNS),
+		ConnectMS:      milliseconds(t.Connect),
+		TLSHandshakeMS: milliseconds(t.TLSHandshake),
+		FirstByteMS:    milliseconds(t.FirstByte),
+	})
+}
+
+// MarshalJSON encodes the snapshot with response times in milliseconds.
+func (s StatsSnapshot) MarshalJSON() ([]byte, error) {
+	type plainSnapshot StatsSnapshot
+
+	return json.Marshal(struct {
+		plainSnapshot
+		AvgResponseMS float64 `json:"avg_response_ms"`
+		P50ResponseMS float64 `json:"p50_response_ms"`
+		P95ResponseMS float64 `json:"p95_response_ms"`
+	}{
+		plainSnapshot: plainSnapshot(s),
+		AvgResponseMS: milliseconds(s.AvgResponse),
+		P50ResponseMS: milliseconds(s.P50Response),
+		P95ResponseMS: milliseconds(s.P95Response),
+	})
+}
+
+// milliseconds converts d to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
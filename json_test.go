@@ -0,0 +1,72 @@
+package gomon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCheckResult_MarshalJSON(t *testing.T) {
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	result := &CheckResult{
+		URL:        "https://example.com",
+		StatusCode: 200,
+		Up:         true,
+		Start:      start,
+		End:        start.Add(1500 * time.Millisecond),
+		Timings:    Timings{DNS: 250 * time.Millisecond},
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got["start"] != "2024-10-01T12:00:00Z" {
+		t.Errorf("start = %v, want RFC 3339 time", got["start"])
+	}
+	if got["duration_ms"] != 1500.0 {
+		t.Errorf("duration_ms = %v, want 1500", got["duration_ms"])
+	}
+	if timings, _ := got["timings"].(map[string]any); timings["dns_ms"] != 250.0 {
+		t.Errorf("timings.dns_ms = %v, want 250", timings["dns_ms"])
+	}
+	if _, ok := got["cert_info"]; ok {
+		t.Errorf("cert_info present for nil CertInfo")
+	}
+}
+
+func TestStatsSnapshot_MarshalJSON(t *testing.T) {
+	snapshot := StatsSnapshot{
+		Checks:      4,
+		Uptime:      0.75,
+		AvgResponse: 120 * time.Millisecond,
+		P50Response: 100 * time.Millisecond,
+		P95Response: 2500 * time.Microsecond,
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := map[string]float64{"checks": 4, "avg_response_ms": 120, "p50_response_ms": 100, "p95_response_ms": 2.5}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+	if _, ok := got["avg_response"]; ok {
+		t.Errorf("avg_response present without a unit")
+	}
+}
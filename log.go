@@ -0,0 +1,51 @@
+package gomon
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler is a slog.Handler that discards all records. It is used
+// when Config.Logger is nil.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// discardLogger is used by a Monitor without a logger.
+var discardLogger = slog.New(discardHandler{})
+
+// newLogger returns the logger used by a monitor for config.
+func newLogger(config Config) *slog.Logger {
+	if config.Logger == nil {
+		return discardLogger
+	}
+
+	return config.Logger.With("url", config.URL, "method", config.Method)
+}
+
+// logger returns the logger of m, which discards messages if none is set.
+func (m *Monitor) logger() *slog.Logger {
+	if m.log == nil {
+		return discardLogger
+	}
+
+	return m.log
+}
+
+// logOutcome logs the final outcome of a check.
+func (m *Monitor) logOutcome(ctx context.Context, result *CheckResult, err error) {
+	if err != nil {
+		m.logger().DebugContext(ctx, "check failed", "error", err)
+		return
+	}
+
+	m.logger().DebugContext(ctx, "check finished",
+		"up", result.Up,
+		"status", result.StatusCode,
+		"duration", result.End.Sub(result.Start),
+		"attempts", result.Attempts,
+		"reasons", result.Reasons())
+}
@@ -0,0 +1,82 @@
+package gomon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckLogger(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/end", http.StatusFound)
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	m, err := NewMonitor(Config{
+		URL:             ts.URL + "/start",
+		Method:          http.MethodGet,
+		IgnoreCert:      true,
+		RetryCount:      1,
+		RetryDelay:      time.Millisecond,
+		RetryDownStatus: true,
+		Logger:          logger,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	if _, err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	var messages []string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record struct {
+			Msg string `json:"msg"`
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if record.URL != m.config.URL {
+			t.Errorf("record %q has url %q, want %q", record.Msg, record.URL, m.config.URL)
+		}
+		messages = append(messages, record.Msg)
+	}
+
+	want := []string{
+		"check started", "redirect", "tls", "retrying",
+		"check started", "redirect", "tls", "check finished",
+	}
+	if !slices.Equal(messages, want) {
+		t.Errorf("logged %q, want %q", messages, want)
+	}
+}
+
+func TestMonitor_CheckNoLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// A Monitor not built by NewMonitor has no logger.
+	m := &Monitor{client: &http.Client{}, config: Config{URL: ts.URL, Method: http.MethodGet}}
+	if _, err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+// Package metrics exposes gomon check results as Prometheus metrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bnixon67/gomon"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labels identify the monitored site for each metric.
+var labels = []string{"url", "method"}
+
+var (
+	upDesc = prometheus.NewDesc(
+		"gomon_up",
+		"Whether the last check found the site up (1) or down (0).",
+		labels, nil,
+	)
+	durationDesc = prometheus.NewDesc(
+		"gomon_response_duration_seconds",
+		"Duration of the last check in seconds.",
+		labels, nil,
+	)
+	certExpiryDesc = prometheus.NewDesc(
+		"gomon_ssl_cert_expiry_seconds",
+		"Seconds until the certificate seen by the last check expires.",
+		labels, nil,
+	)
+	checksDesc = prometheus.NewDesc(
+		"gomon_check_total",
+		"Total number of checks observed.",
+		labels, nil,
+	)
+)
+
+// site is the key for per-site state.
+type site struct {
+	url    string
+	method string
+}
+
+// siteState is the most recent state observed for a site.
+type siteState struct {
+	result *gomon.CheckResult
+	checks uint64
+}
+
+// Collector is a prometheus.Collector reporting the most recent result
+// observed for each site. It is safe for concurrent use.
+type Collector struct {
+	mu    sync.Mutex
+	sites map[site]*siteState
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{sites: make(map[site]*siteState)}
+}
+
+// Observe records a check result. Nil results are ignored.
+func (c *Collector) Observe(result *gomon.CheckResult) {
+	if result == nil {
+		return
+	}
+
+	key := site{url: result.URL, method: result.Method}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.sites[key]
+	if !ok {
+		state = &siteState{}
+		c.sites[key] = state
+	}
+	state.result = result
+	state.checks++
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- durationDesc
+	ch <- certExpiryDesc
+	ch <- checksDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, state := range c.sites {
+		result := state.result
+
+		up := 0.0
+		if result.IsUp() {
+			up = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue,
+			up, key.url, key.method)
+		ch <- prometheus.MustNewConstMetric(durationDesc, prometheus.GaugeValue,
+			result.End.Sub(result.Start).Seconds(), key.url, key.method)
+		ch <- prometheus.MustNewConstMetric(checksDesc, prometheus.CounterValue,
+			float64(state.checks), key.url, key.method)
+
+		if result.CertInfo != nil {
+			ch <- prometheus.MustNewConstMetric(certExpiryDesc, prometheus.GaugeValue,
+				time.Until(result.CertInfo.ValidTo).Seconds(), key.url, key.method)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bnixon67/gomon"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	start := time.Now()
+
+	c := NewCollector()
+	c.Observe(&gomon.CheckResult{
+		URL:    "https://example.com",
+		Method: "GET",
+		Up:     false,
+		Start:  start,
+		End:    start.Add(time.Second),
+	})
+	c.Observe(&gomon.CheckResult{
+		URL:    "https://example.com",
+		Method: "GET",
+		Up:     true,
+		Start:  start,
+		End:    start.Add(250 * time.Millisecond),
+	})
+	c.Observe(nil)
+
+	expected := `
+# HELP gomon_check_total Total number of checks observed.
+# TYPE gomon_check_total counter
+gomon_check_total{method="GET",url="https://example.com"} 2
+# HELP gomon_response_duration_seconds Duration of the last check in seconds.
+# TYPE gomon_response_duration_seconds gauge
+gomon_response_duration_seconds{method="GET",url="https://example.com"} 0.25
+# HELP gomon_up Whether the last check found the site up (1) or down (0).
+# TYPE gomon_up gauge
+gomon_up{method="GET",url="https://example.com"} 1
+`
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"gomon_check_total", "gomon_response_duration_seconds", "gomon_up")
+	if err != nil {
+		t.Errorf("CollectAndCompare() error = %v", err)
+	}
+
+	if got := testutil.CollectAndCount(c, "gomon_ssl_cert_expiry_seconds"); got != 0 {
+		t.Errorf("cert expiry metrics = %d, want 0 without CertInfo", got)
+	}
+}
@@ -0,0 +1,109 @@
+package gomon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Notifier is notified when a site monitored by Run changes between up and
+// down. prev is the last result in the previous state and curr is the
+// result that confirmed the new state.
+type Notifier interface {
+	OnStateChange(prev, curr *CheckResult)
+}
+
+// stateTracker detects confirmed transitions between up and down.
+type stateTracker struct {
+	confirm int          // consecutive results needed to change state
+	last    *CheckResult // most recent result in the current state
+	pending int          // consecutive results disagreeing with last
+}
+
+// observe records result and, if it confirms a change of state, returns
+// the last result of the previous state and true.
+func (s *stateTracker) observe(result *CheckResult) (*CheckResult, bool) {
+	if s.last == nil {
+		s.last = result
+		return nil, false
+	}
+
+	if result.IsUp() == s.last.IsUp() {
+		s.last = result
+		s.pending = 0
+		return nil, false
+	}
+
+	s.pending++
+	if s.pending < s.confirm {
+		return nil, false
+	}
+
+	prev := s.last
+	s.last = result
+	s.pending = 0
+
+	return prev, true
+}
+
+// WebhookNotifier posts a JSON description of each state change to URL.
+type WebhookNotifier struct {
+	URL string
+
+	// Client sends the webhook. If nil, a client with a 10 second timeout
+	// is used.
+	Client *http.Client
+
+	// OnError, if set, is called when the webhook cannot be delivered.
+	OnError func(error)
+}
+
+// webhookClient is the default client for WebhookNotifier.
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// webhookPayload is the JSON body sent by WebhookNotifier.
+type webhookPayload struct {
+	URL      string       `json:"url"`
+	Up       bool         `json:"up"`
+	Previous *CheckResult `json:"previous"`
+	Current  *CheckResult `json:"current"`
+}
+
+// OnStateChange implements Notifier.
+func (w *WebhookNotifier) OnStateChange(prev, curr *CheckResult) {
+	if err := w.send(prev, curr); err != nil && w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// send posts the state change to the webhook.
+func (w *WebhookNotifier) send(prev, curr *CheckResult) error {
+	body, err := json.Marshal(webhookPayload{
+		URL:      curr.URL,
+		Up:       curr.IsUp(),
+		Previous: prev,
+		Current:  curr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = webhookClient
+	}
+
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package gomon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStateTracker(t *testing.T) {
+	tests := []struct {
+		name    string
+		confirm int
+		states  []bool
+		want    []int // indexes of results that change state
+	}{
+		{name: "Steady", confirm: 1, states: []bool{true, true, true}, want: nil},
+		{name: "Down and up", confirm: 1, states: []bool{true, false, false, true}, want: []int{1, 3}},
+		{name: "Flap suppressed", confirm: 2, states: []bool{true, false, true, false, true}, want: nil},
+		{name: "Confirmed down", confirm: 2, states: []bool{true, false, false, false}, want: []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := stateTracker{confirm: tt.confirm}
+
+			var got []int
+			for i, up := range tt.states {
+				result := &CheckResult{Up: up}
+				if prev, changed := tracker.observe(result); changed {
+					if prev.IsUp() == up {
+						t.Errorf("result %d: prev.Up = curr.Up = %v", i, up)
+					}
+					got = append(got, i)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("state changes at %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got webhookPayload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	var notifyErr error
+	n := &WebhookNotifier{URL: ts.URL, OnError: func(err error) { notifyErr = err }}
+
+	n.OnStateChange(
+		&CheckResult{URL: "https://example.com", Up: true},
+		&CheckResult{URL: "https://example.com", Up: false, StatusCode: 503},
+	)
+
+	if notifyErr != nil {
+		t.Fatalf("OnError called with %v", notifyErr)
+	}
+	if got.URL != "https://example.com" || got.Up {
+		t.Errorf("payload = %+v, want down event for https://example.com", got)
+	}
+	if got.Previous == nil || !got.Previous.Up || got.Current == nil || got.Current.StatusCode != 503 {
+		t.Errorf("payload results = %+v, %+v", got.Previous, got.Current)
+	}
+}
+
+func TestWebhookNotifier_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var notifyErr error
+	n := &WebhookNotifier{URL: ts.URL, OnError: func(err error) { notifyErr = err }}
+	n.OnStateChange(&CheckResult{Up: true}, &CheckResult{})
+
+	if notifyErr == nil {
+		t.Errorf("OnError not called for failed webhook")
+	}
+}
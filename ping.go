@@ -0,0 +1,60 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Ping sends a single HEAD request to the configured URL and returns its
+// latency and status code, for frequent liveness probes where Check does
+// more than needed. It uses the monitor's client, timeout and request
+// headers, but does not retry, read the body, inspect the certificate or
+// judge the status, which is returned whatever its value. For a tcp:// URL,
+// Ping times opening a connection and returns a status of 0. Failures are
+// reported as a *CheckError.
+func (m *Monitor) Ping(ctx context.Context) (time.Duration, int, error) {
+	if m.tcp {
+		return m.pingTCP(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, m.config.URL, nil)
+	if err != nil {
+		return 0, 0, &CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err}
+	}
+	if err := m.prepareRequest(req, newCheckID()); err != nil {
+		return 0, 0, &CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err}
+	}
+
+	start := time.Now()
+	resp, err := m.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, 0, &CheckError{Phase: sendPhase(err), URL: m.config.URL, Err: classifyContextErr(ctx, err)}
+	}
+	resp.Body.Close()
+
+	return latency, resp.StatusCode, nil
+}
+
+// pingTCP times opening a TCP connection to the configured host and port.
+func (m *Monitor) pingTCP(ctx context.Context) (time.Duration, int, error) {
+	parsedURL, err := url.Parse(m.config.URL)
+	if err != nil {
+		return 0, 0, &CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err}
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, m.config.RequestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := m.dial(dialCtx, "tcp", parsedURL.Host)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, 0, &CheckError{Phase: sendPhase(err), URL: m.config.URL, Err: classifyContextErr(ctx, err)}
+	}
+	conn.Close()
+
+	return latency, 0, nil
+}
@@ -0,0 +1,109 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMonitor_Ping(t *testing.T) {
+	var method, userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, userAgent = r.Method, r.UserAgent()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, UserAgent: "probe/1.0"})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	latency, status, err := m.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+	if latency <= 0 {
+		t.Errorf("latency = %v, want > 0", latency)
+	}
+	if method != http.MethodHead || userAgent != "probe/1.0" {
+		t.Errorf("server received %s with User-Agent %q, want HEAD with probe/1.0", method, userAgent)
+	}
+}
+
+func TestMonitor_PingTokenProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	errExpired := errors.New("refresh token expired")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Token sent", wantStatus: http.StatusOK},
+		{name: "Provider error", err: errExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:    ts.URL,
+				Method: http.MethodGet,
+				TokenProvider: func(context.Context) (string, error) {
+					return "fresh", tt.err
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			_, status, err := m.Ping(context.Background())
+			if tt.err != nil {
+				var checkErr *CheckError
+				if !errors.Is(err, tt.err) || !errors.As(err, &checkErr) || checkErr.Phase != PhaseRequest {
+					t.Errorf("Ping() error = %v, want %v in request phase", err, tt.err)
+				}
+				return
+			}
+			if err != nil || status != tt.wantStatus {
+				t.Errorf("Ping() status = %d, error = %v, want %d", status, err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMonitor_PingTCP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	addr := strings.TrimPrefix(ts.URL, "http://")
+
+	m, err := NewMonitor(Config{URL: "tcp://" + addr})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	if _, status, err := m.Ping(context.Background()); err != nil || status != 0 {
+		t.Errorf("Ping() status = %d, error = %v, want 0 and nil", status, err)
+	}
+
+	ts.Close()
+
+	_, _, err = m.Ping(context.Background())
+	var checkErr *CheckError
+	var opErr *net.OpError
+	if !errors.As(err, &checkErr) || checkErr.Phase != PhaseConnect || !errors.As(err, &opErr) {
+		t.Errorf("Ping() error = %v, want connect error", err)
+	}
+}
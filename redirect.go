@@ -0,0 +1,105 @@
+package gomon
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxRedirects is the number of redirects followed when MaxRedirects
+// is not set.
+const DefaultMaxRedirects = 10
+
+// RedirectError reports that a check stopped following redirects, either
+// because a URL repeated or because MaxRedirects was exceeded.
+type RedirectError struct {
+	Chain []string      // URLs visited, in order, ending with the rejected one
+	Hops  []RedirectHop // redirects followed, including the rejected one
+	Loop  bool          // whether the last URL was already visited
+}
+
+// Error implements the error interface.
+func (e *RedirectError) Error() string {
+	if e.Loop {
+		return fmt.Sprintf("redirect loop: %s", strings.Join(e.Chain, " -> "))
+	}
+
+	return fmt.Sprintf("too many redirects (%d): %s", len(e.Chain)-1, strings.Join(e.Chain, " -> "))
+}
+
+// noRedirect disables HTTP redirects.
+func noRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// limitRedirects returns a CheckRedirect function that stops at a redirect
+// loop or after maxRedirects redirects. URLs are compared without the
+// cache-busting parameter param.
+func limitRedirects(maxRedirects int, param string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		chain := make([]string, 0, len(via)+1)
+		for _, r := range via {
+			chain = append(chain, stripCacheBust(r.URL, param))
+		}
+		next := stripCacheBust(req.URL, param)
+
+		loop := slices.Contains(chain, next)
+		if loop || len(via) > maxRedirects {
+			return &RedirectError{Chain: append(chain, next), Hops: redirectChain(req, param), Loop: loop}
+		}
+
+		return nil
+	}
+}
+
+// RedirectHop is one redirect followed during a check.
+type RedirectHop struct {
+	URL        string `json:"url"`         // URL that responded with the redirect
+	StatusCode int    `json:"status_code"` // status of the redirect response
+}
+
+// String returns the status code and URL of the hop.
+func (h RedirectHop) String() string {
+	return fmt.Sprintf("%d %s", h.StatusCode, h.URL)
+}
+
+// redirectChain returns the redirects followed to reach req, in order.
+func redirectChain(req *http.Request, param string) []RedirectHop {
+	var chain []RedirectHop
+	for r := req; r.Response != nil; r = r.Response.Request {
+		chain = append(chain, RedirectHop{
+			URL:        stripCacheBust(r.Response.Request.URL, param),
+			StatusCode: r.Response.StatusCode,
+		})
+	}
+	slices.Reverse(chain)
+
+	return chain
+}
+
+// redirectLocation returns the Location of resp, resolved against the
+// request URL, if resp is a redirect that was not followed. It returns ""
+// otherwise.
+func redirectLocation(resp *http.Response, param string) string {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return ""
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return ""
+	}
+
+	return stripCacheBust(location, param)
+}
+
+// matchLocation reports whether location matches want, which matches any
+// location with its prefix if it ends in "*".
+func matchLocation(want, location string) bool {
+	if prefix, ok := strings.CutSuffix(want, "*"); ok {
+		return strings.HasPrefix(location, prefix)
+	}
+
+	return location == want
+}
@@ -0,0 +1,223 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMonitor_CheckRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("n"))
+		if n == 0 {
+			return
+		}
+		status := http.StatusFound
+		if n%2 == 0 {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), status)
+	})
+	mux.HandleFunc("/loop/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop/b", http.StatusFound)
+	})
+	mux.HandleFunc("/loop/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop/a", http.StatusFound)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		maxRedirects int
+		wantChain    []RedirectHop
+		wantErrChain []string
+		wantLoop     bool
+	}{
+		{
+			name:         "Within limit",
+			path:         "/hop/2",
+			maxRedirects: 2,
+			wantChain: []RedirectHop{
+				{URL: "/hop/2", StatusCode: http.StatusMovedPermanently},
+				{URL: "/hop/1", StatusCode: http.StatusFound},
+			},
+		},
+		{
+			name:         "Too many redirects",
+			path:         "/hop/3",
+			maxRedirects: 2,
+			wantChain: []RedirectHop{
+				{URL: "/hop/3", StatusCode: http.StatusFound},
+				{URL: "/hop/2", StatusCode: http.StatusMovedPermanently},
+				{URL: "/hop/1", StatusCode: http.StatusFound},
+			},
+			wantErrChain: []string{"/hop/3", "/hop/2", "/hop/1", "/hop/0"},
+		},
+		{
+			name: "Loop",
+			path: "/loop/a",
+			wantChain: []RedirectHop{
+				{URL: "/loop/a", StatusCode: http.StatusFound},
+				{URL: "/loop/b", StatusCode: http.StatusFound},
+			},
+			wantErrChain: []string{"/loop/a", "/loop/b", "/loop/a"},
+			wantLoop:     true,
+		},
+	}
+
+	prefix := func(paths []string) []string {
+		var urls []string
+		for _, p := range paths {
+			urls = append(urls, ts.URL+p)
+		}
+		return urls
+	}
+
+	prefixHops := func(hops []RedirectHop) []RedirectHop {
+		var prefixed []RedirectHop
+		for _, hop := range hops {
+			prefixed = append(prefixed, RedirectHop{URL: ts.URL + hop.URL, StatusCode: hop.StatusCode})
+		}
+		return prefixed
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL + tt.path,
+				Method:       http.MethodGet,
+				MaxRedirects: tt.maxRedirects,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if want := prefixHops(tt.wantChain); !reflect.DeepEqual(got.RedirectChain, want) {
+				t.Errorf("RedirectChain = %v, want %v", got.RedirectChain, want)
+			}
+
+			if tt.wantErrChain != nil {
+				var redirectErr *RedirectError
+				if !errors.As(err, &redirectErr) {
+					t.Fatalf("Check() error = %v, want *RedirectError", err)
+				}
+				if !reflect.DeepEqual(redirectErr.Chain, prefix(tt.wantErrChain)) {
+					t.Errorf("Chain = %q, want %q", redirectErr.Chain, prefix(tt.wantErrChain))
+				}
+				if redirectErr.Loop != tt.wantLoop {
+					t.Errorf("Loop = %v, want %v", redirectErr.Loop, tt.wantLoop)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckAcceptRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com/", http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name            string
+		acceptRedirects bool
+		upStatusCodes   []int
+		wantUp          bool
+	}{
+		{name: "Default", wantUp: false},
+		{name: "Accepted", acceptRedirects: true, wantUp: true},
+		{name: "Accepted with status codes", acceptRedirects: true, upStatusCodes: []int{200}, wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                ts.URL,
+				Method:             http.MethodGet,
+				DontFollowRedirect: true,
+				AcceptRedirects:    tt.acceptRedirects,
+				UpStatusCodes:      tt.upStatusCodes,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.StatusCode != http.StatusMovedPermanently {
+				t.Errorf("StatusCode = %d, want %d", got.StatusCode, http.StatusMovedPermanently)
+			}
+			if got.Up != tt.wantUp {
+				t.Errorf("Up = %v, want %v", got.Up, tt.wantUp)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRedirectLocation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/upgrade":
+			http.Redirect(w, r, "https://example.com/upgrade", http.StatusMovedPermanently)
+		case "/relative":
+			http.Redirect(w, r, "/canonical/", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		expected     string
+		wantLocation string
+		wantUp       bool
+	}{
+		{name: "Exact", path: "/upgrade", expected: "https://example.com/upgrade", wantLocation: "https://example.com/upgrade", wantUp: true},
+		{name: "Prefix", path: "/upgrade", expected: "https://example.com/*", wantLocation: "https://example.com/upgrade", wantUp: true},
+		{name: "Mismatch", path: "/upgrade", expected: "https://www.example.com/*", wantLocation: "https://example.com/upgrade"},
+		{name: "Relative", path: "/relative", expected: ts.URL + "/canonical/", wantLocation: ts.URL + "/canonical/", wantUp: true},
+		{name: "Not a redirect", path: "/", expected: "https://example.com/", wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:                      ts.URL + tt.path,
+				Method:                   http.MethodGet,
+				DontFollowRedirect:       true,
+				AcceptRedirects:          true,
+				ExpectedRedirectLocation: tt.expected,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.RedirectLocation != tt.wantLocation {
+				t.Errorf("RedirectLocation = %q, want %q", got.RedirectLocation, tt.wantLocation)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gomon
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxRetryDelay caps retry delays, whatever MaxDelay, so that a delay
+// growing without limit neither overflows time.Duration nor does so once
+// jitter is added.
+const maxRetryDelay = time.Duration(math.MaxInt64 / 2)
+
+// RetryPolicy controls how Check retries a failed attempt. Delays grow
+// from BaseDelay by Multiplier after each retry, up to MaxDelay, and are
+// cut short when the context passed to Check is done.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 mean a single attempt.
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry. Multiplier, if greater
+	// than 1, multiplies the delay after each retry, and MaxDelay, if
+	// positive, caps it.
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	Multiplier float64
+
+	// Jitter randomly varies each delay by up to this fraction of it in
+	// either direction, so that monitors failing together do not retry in
+	// step. It is between 0 and 0.5.
+	Jitter float64
+
+	// Retryable decides whether an attempt is retried, given its error and
+	// result, which is never nil. It defaults to RetryOnError.
+	Retryable func(err error, result *CheckResult) bool
+}
+
+// DefaultRetryPolicy makes up to three attempts, retrying errors after
+// about half a second and then a second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Multiplier:  2,
+	Jitter:      0.2,
+}
+
+// RetryOnError retries attempts that failed with an error.
+func RetryOnError(err error, _ *CheckResult) bool {
+	return err != nil
+}
+
+// RetryOnDown retries attempts that failed with an error or found the site
+// down.
+func RetryOnDown(err error, result *CheckResult) bool {
+	return err != nil || !result.IsUp()
+}
+
+// retryPolicy returns the policy described by config: its RetryPolicy, or
+// one built from RetryCount, RetryDelay, RetryBackoff and RetryDownStatus.
+func retryPolicy(config Config) (RetryPolicy, error) {
+	if config.RetryPolicy == nil {
+		policy := RetryPolicy{
+			MaxAttempts: config.RetryCount + 1,
+			BaseDelay:   config.RetryDelay,
+			Multiplier:  config.RetryBackoff,
+			Retryable:   RetryOnError,
+		}
+		if config.RetryDownStatus {
+			policy.Retryable = RetryOnDown
+		}
+		return policy, nil
+	}
+
+	if config.RetryCount != 0 || config.RetryDelay != 0 || config.RetryBackoff != 0 || config.RetryDownStatus {
+		return RetryPolicy{}, fmt.Errorf("both RetryPolicy and RetryCount, RetryDelay, RetryBackoff or RetryDownStatus set")
+	}
+
+	policy := *config.RetryPolicy
+
+	switch {
+	case policy.BaseDelay < 0:
+		return RetryPolicy{}, fmt.Errorf("negative retry delay")
+	case policy.MaxDelay < 0:
+		return RetryPolicy{}, fmt.Errorf("negative max retry delay")
+	case policy.Multiplier < 0:
+		return RetryPolicy{}, fmt.Errorf("negative retry multiplier")
+	case policy.Jitter < 0 || policy.Jitter > 0.5:
+		return RetryPolicy{}, fmt.Errorf("retry jitter %g is not between 0 and 0.5", policy.Jitter)
+	}
+
+	policy.MaxAttempts = max(policy.MaxAttempts, 1)
+	if policy.Retryable == nil {
+		policy.Retryable = RetryOnError
+	}
+
+	return policy, nil
+}
+
+// delay returns the wait before retry number n, counting from 1.
+func (p RetryPolicy) delay(n int) time.Duration {
+	limit := maxRetryDelay
+	if p.MaxDelay > 0 {
+		limit = min(p.MaxDelay, maxRetryDelay)
+	}
+
+	d := float64(p.BaseDelay)
+	if p.Multiplier > 1 {
+		for range n - 1 {
+			d *= p.Multiplier
+			if d >= float64(limit) {
+				break
+			}
+		}
+	}
+
+	delay := limit
+	if d < float64(limit) {
+		delay = time.Duration(d)
+	}
+
+	return jittered(delay, time.Duration(float64(delay)*p.Jitter))
+}
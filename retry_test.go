@@ -0,0 +1,125 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Multiplier: 3,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		300 * time.Millisecond,
+		900 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, w := range want {
+		if got := policy.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.delay(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("delay(1) with jitter = %v, want within [50ms, 150ms]", got)
+		}
+	}
+}
+
+func TestRetryPolicy_DelayUnbounded(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:  time.Second,
+		Multiplier: 10,
+		Jitter:     0.5,
+	}
+
+	// Without MaxDelay, the delay grows until capped rather than
+	// overflowing to a negative or zero wait.
+	for n := 1; n <= 100; n++ {
+		got := policy.delay(n)
+		if got <= 0 {
+			t.Fatalf("delay(%d) = %v, want positive", n, got)
+		}
+		if n >= 20 && got < maxRetryDelay/2 {
+			t.Errorf("delay(%d) = %v, want at least %v", n, got, maxRetryDelay/2)
+		}
+	}
+}
+
+func TestMonitor_CheckRetryPolicy(t *testing.T) {
+	retryUnavailable := func(err error, result *CheckResult) bool {
+		return err != nil || result.StatusCode == http.StatusServiceUnavailable
+	}
+
+	tests := []struct {
+		name         string
+		status       int
+		policy       RetryPolicy
+		wantAttempts int32
+	}{
+		{name: "Retry on 503", status: http.StatusServiceUnavailable, policy: RetryPolicy{MaxAttempts: 3, Retryable: retryUnavailable}, wantAttempts: 3},
+		{name: "No retry on 404", status: http.StatusNotFound, policy: RetryPolicy{MaxAttempts: 3, Retryable: retryUnavailable}, wantAttempts: 1},
+		{name: "Default retries errors only", status: http.StatusServiceUnavailable, policy: RetryPolicy{MaxAttempts: 3}, wantAttempts: 1},
+		{name: "Retry down", status: http.StatusNotFound, policy: RetryPolicy{MaxAttempts: 2, Retryable: RetryOnDown}, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			m, err := NewMonitor(Config{
+				URL:         ts.URL,
+				Method:      http.MethodGet,
+				RetryPolicy: &tt.policy,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if attempts.Load() != tt.wantAttempts || got.Attempts != int(tt.wantAttempts) {
+				t.Errorf("server saw %d attempts, Attempts = %d, want %d", attempts.Load(), got.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestNewMonitor_RetryPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "With RetryCount", config: Config{RetryPolicy: &DefaultRetryPolicy, RetryCount: 1}},
+		{name: "Negative delay", config: Config{RetryPolicy: &RetryPolicy{BaseDelay: -time.Second}}},
+		{name: "Jitter too large", config: Config{RetryPolicy: &RetryPolicy{Jitter: 0.8}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.URL = "https://example.com"
+			tt.config.Method = http.MethodGet
+			if _, err := NewMonitor(tt.config); err == nil {
+				t.Errorf("NewMonitor() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package gomon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// RevocationStatus is the OCSP revocation status of a certificate.
+type RevocationStatus string
+
+const (
+	RevocationGood    RevocationStatus = "Good"
+	RevocationRevoked RevocationStatus = "Revoked"
+	RevocationUnknown RevocationStatus = "Unknown"
+)
+
+// maxOCSPResponseBytes bounds the size of an OCSP response.
+const maxOCSPResponseBytes = 64 << 10
+
+// issuerOf returns the certificate that issued the leaf of tlsState, or nil
+// if the issuer is not known.
+func issuerOf(tlsState *tls.ConnectionState) *x509.Certificate {
+	if len(tlsState.VerifiedChains) > 0 && len(tlsState.VerifiedChains[0]) > 1 {
+		return tlsState.VerifiedChains[0][1]
+	}
+
+	if len(tlsState.PeerCertificates) > 1 {
+		return tlsState.PeerCertificates[1]
+	}
+
+	return nil
+}
+
+// newOCSPClient creates the client for the OCSP requests of config. The
+// responder is a different host from the site, so the client uses only the
+// proxy, network, resolver and dial timeout of config, not the settings
+// that direct connections to the site.
+func newOCSPClient(config Config) (*http.Client, error) {
+	proxy, err := proxyFunc(config.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := configResolver(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: config.DialTimeout, Resolver: res}
+	dial := dialer.DialContext
+	if config.Network != "" {
+		dial = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, config.Network, addr)
+		}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:             proxy,
+			DialContext:       dial,
+			DisableKeepAlives: config.DisableKeepAlives,
+			ForceAttemptHTTP2: true,
+		},
+	}, nil
+}
+
+// revocationStatus queries the OCSP responder of cert using client,
+// allowing at most timeout. Any failure to obtain a definitive answer
+// results in RevocationUnknown.
+func revocationStatus(ctx context.Context, client *http.Client, timeout time.Duration, cert, issuer *x509.Certificate) RevocationStatus {
+	if issuer == nil || len(cert.OCSPServer) == 0 {
+		return RevocationUnknown
+	}
+
+	ocspReq, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return RevocationUnknown
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cert.OCSPServer[0], bytes.NewReader(ocspReq))
+	if err != nil {
+		return RevocationUnknown
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RevocationUnknown
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RevocationUnknown
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseBytes))
+	if err != nil {
+		return RevocationUnknown
+	}
+
+	ocspResp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return RevocationUnknown
+	}
+
+	switch ocspResp.Status {
+	case ocsp.Good:
+		return RevocationGood
+	case ocsp.Revoked:
+		return RevocationRevoked
+	default:
+		return RevocationUnknown
+	}
+}
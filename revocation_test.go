@@ -0,0 +1,171 @@
+package gomon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocationStatus(t *testing.T) {
+	ca, caKey := newTestCA(t)
+
+	tests := []struct {
+		name   string
+		status int
+		fail   bool
+		want   RevocationStatus
+	}{
+		{name: "Good", status: ocsp.Good, want: RevocationGood},
+		{name: "Revoked", status: ocsp.Revoked, want: RevocationRevoked},
+		{name: "Responder failure", fail: true, want: RevocationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var leaf *x509.Certificate
+
+			responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.fail {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				if _, err := io.ReadAll(r.Body); err != nil {
+					t.Errorf("ReadAll() error = %v", err)
+				}
+
+				resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+					Status:       tt.status,
+					SerialNumber: leaf.SerialNumber,
+					ThisUpdate:   time.Now().Add(-time.Minute),
+					NextUpdate:   time.Now().Add(time.Hour),
+					RevokedAt:    time.Now().Add(-time.Minute),
+				}, caKey)
+				if err != nil {
+					t.Errorf("CreateResponse() error = %v", err)
+				}
+				w.Write(resp)
+			}))
+			defer responder.Close()
+
+			leaf, _ = newTestCert(t, &x509.Certificate{
+				SerialNumber: big.NewInt(2),
+				Subject:      pkix.Name{CommonName: "localhost"},
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     time.Now().Add(time.Hour),
+				OCSPServer:   []string{responder.URL},
+			}, ca, caKey)
+
+			got := revocationStatus(context.Background(), http.DefaultClient, 5*time.Second, leaf, ca)
+			if got != tt.want {
+				t.Errorf("revocationStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationStatus_NoIssuer(t *testing.T) {
+	leaf, _ := newTestCA(t)
+
+	if got := revocationStatus(context.Background(), http.DefaultClient, time.Second, leaf, nil); got != RevocationUnknown {
+		t.Errorf("revocationStatus() = %q, want %q", got, RevocationUnknown)
+	}
+}
+
+func TestMonitor_CheckRevocationProxy(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leaf, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		OCSPServer:   []string{"http://ocsp.example.invalid/"},
+	}, ca, caKey)
+
+	// The responder's host does not resolve, so it is reachable only
+	// through the proxy, which answers for it.
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: leaf.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, caKey)
+		if err != nil {
+			t.Errorf("CreateResponse() error = %v", err)
+		}
+		w.Write(resp)
+	}))
+	defer proxy.Close()
+
+	m, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet, Proxy: proxy.URL, CheckRevocation: true})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	info := &CertInfo{IsValid: true}
+	m.checkRevocation(context.Background(), info, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}})
+	if info.Revocation != RevocationGood {
+		t.Errorf("Revocation = %q, want %q", info.Revocation, RevocationGood)
+	}
+	if proxiedHost != "ocsp.example.invalid" {
+		t.Errorf("proxy received host %q, want %q", proxiedHost, "ocsp.example.invalid")
+	}
+}
+
+func TestMonitor_CheckRevocationDialIP(t *testing.T) {
+	ca, caKey := newTestCA(t)
+
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: big.NewInt(2),
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, caKey)
+		if err != nil {
+			t.Errorf("CreateResponse() error = %v", err)
+		}
+		w.Write(resp)
+	}))
+	defer responder.Close()
+
+	leaf, _ := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		OCSPServer:   []string{responder.URL},
+	}, ca, caKey)
+
+	// The site shares the responder's host, but its connections are
+	// directed elsewhere, which must not apply to the responder.
+	m, err := NewMonitor(Config{
+		URL:             responder.URL,
+		Method:          http.MethodGet,
+		DialIP:          "192.0.2.1",
+		HostOverride:    "example.com",
+		CheckRevocation: true,
+		RequestTimeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	info := &CertInfo{IsValid: true}
+	m.checkRevocation(context.Background(), info, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}})
+	if info.Revocation != RevocationGood {
+		t.Errorf("Revocation = %q, want %q", info.Revocation, RevocationGood)
+	}
+}
@@ -0,0 +1,90 @@
+package gomon
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RunResult is the outcome of one scheduled check.
+type RunResult struct {
+	Result *CheckResult
+	Err    error
+}
+
+// Run checks the site immediately and then once per interval until ctx is
+// done, delivering each outcome on the returned channel. A check that would
+// start while the previous one is still running is skipped. The channel is
+// closed when Run stops. The interval must be positive; Run panics
+// otherwise, as time.NewTicker does.
+//
+// If Config.Jitter is set, each interval is randomly lengthened or
+// shortened by up to Jitter, so that many monitors started together do not
+// check in lockstep.
+//
+// If Config.Notifier is set, it is called when the site changes between up
+// and down, after Config.ConfirmCount consecutive checks agree. A failed
+// check counts as down.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) <-chan RunResult {
+	if interval <= 0 {
+		panic("gomon: non-positive interval for Monitor.Run")
+	}
+
+	results := make(chan RunResult)
+
+	go func() {
+		defer close(results)
+
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+
+		tracker := stateTracker{confirm: m.config.ConfirmCount}
+		next := time.Now()
+
+		for {
+			result, err := m.Check(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			if m.config.Notifier != nil {
+				if prev, changed := tracker.observe(result); changed {
+					m.config.Notifier.OnStateChange(prev, result)
+				}
+			}
+
+			select {
+			case results <- RunResult{Result: result, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			// Skip any check that would have started while this one was
+			// running.
+			next = next.Add(jittered(interval, m.config.Jitter))
+			if behind := time.Since(next); behind > 0 {
+				next = next.Add((behind/interval + 1) * interval)
+			}
+
+			timer.Reset(time.Until(next))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// jittered returns interval adjusted by a random amount in [-jitter,
+// jitter]. The jitter is capped at half the interval.
+func jittered(interval, jitter time.Duration) time.Duration {
+	jitter = min(jitter, interval/2)
+	if jitter <= 0 {
+		return interval
+	}
+
+	return interval - jitter + rand.N(2*jitter+1)
+}
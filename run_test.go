@@ -0,0 +1,133 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMonitor_Run(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := m.Run(ctx, 10*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		r, ok := <-results
+		if !ok {
+			t.Fatalf("channel closed after %d results", i)
+		}
+		if r.Err != nil || !r.Result.IsUp() {
+			t.Errorf("result %d: Err = %v, Up = %v", i, r.Err, r.Result.IsUp())
+		}
+	}
+
+	cancel()
+	for range results {
+	}
+}
+
+func TestMonitor_RunInvalidInterval(t *testing.T) {
+	m, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Run(%s) did not panic", interval)
+				}
+			}()
+			m.Run(context.Background(), interval)
+		}()
+	}
+}
+
+func TestMonitor_RunSkipsOverlap(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := m.Run(ctx, 10*time.Millisecond)
+	<-results
+	first := time.Now()
+	<-results
+
+	// The second check starts on a fresh tick after the first completes.
+	if elapsed := time.Since(first); elapsed < 50*time.Millisecond {
+		t.Errorf("second result after %v, want >= 50ms", elapsed)
+	}
+}
+
+func TestMonitor_RunJitter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	const (
+		interval = 40 * time.Millisecond
+		jitter   = 15 * time.Millisecond
+		slack    = 10 * time.Millisecond // scheduling delay
+	)
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet, Jitter: jitter})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := m.Run(ctx, interval)
+	prev := (<-results).Result.Start
+	for i := 1; i < 6; i++ {
+		start := (<-results).Result.Start
+		if spacing := start.Sub(prev); spacing < interval-jitter-slack || spacing > interval+jitter+slack {
+			t.Errorf("check %d started %v after the previous, want %v ± %v", i, spacing, interval, jitter)
+		}
+		prev = start
+	}
+}
+
+func TestJittered(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		jitter   time.Duration
+		wantMin  time.Duration
+		wantMax  time.Duration
+	}{
+		{name: "No jitter", interval: time.Minute, wantMin: time.Minute, wantMax: time.Minute},
+		{name: "Bounded", interval: time.Minute, jitter: 10 * time.Second, wantMin: 50 * time.Second, wantMax: 70 * time.Second},
+		{name: "Capped", interval: time.Minute, jitter: time.Hour, wantMin: 30 * time.Second, wantMax: 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 1000 {
+				if got := jittered(tt.interval, tt.jitter); got < tt.wantMin || got > tt.wantMax {
+					t.Fatalf("jittered(%v, %v) = %v, want in [%v, %v]", tt.interval, tt.jitter, got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
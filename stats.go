@@ -0,0 +1,113 @@
+package gomon
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Stats accumulates the most recent check results to compute uptime and
+// response time statistics. It is safe for concurrent use.
+type Stats struct {
+	mu         sync.Mutex
+	samples    []sample // ring buffer of recent samples
+	next       int      // index of the next sample to write
+	count      int      // number of samples in use
+	up         bool     // state of the most recent sample
+	lastChange time.Time
+}
+
+// sample is the portion of a CheckResult retained by Stats.
+type sample struct {
+	up       bool
+	duration time.Duration
+	timed    bool // whether duration was measured
+}
+
+// StatsSnapshot is a point-in-time copy of Stats. In JSON, the response
+// times are in milliseconds.
+type StatsSnapshot struct {
+	Checks          int           `json:"checks"`
+	Uptime          float64       `json:"uptime"` // fraction of checks up
+	AvgResponse     time.Duration `json:"-"`
+	P50Response     time.Duration `json:"-"`
+	P95Response     time.Duration `json:"-"`
+	LastStateChange time.Time     `json:"last_state_change"`
+}
+
+// NewStats creates Stats that retains the last size results.
+func NewStats(size int) *Stats {
+	return &Stats{samples: make([]sample, max(size, 1))}
+}
+
+// Add records a check result. A nil result, or that of a failed check,
+// counts as down, and the duration of a failed check is not included in
+// the response times.
+func (s *Stats) Add(result *CheckResult) {
+	smp := sample{up: result.IsUp()}
+	when := time.Now()
+	if result != nil {
+		when = result.End
+		if result.Err == nil {
+			smp.duration = result.End.Sub(result.Start)
+			smp.timed = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastChange.IsZero() || smp.up != s.up {
+		s.lastChange = when
+	}
+	s.up = smp.up
+
+	s.samples[s.next] = smp
+	s.next = (s.next + 1) % len(s.samples)
+	s.count = min(s.count+1, len(s.samples))
+}
+
+// Snapshot returns the current statistics.
+func (s *Stats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := StatsSnapshot{Checks: s.count, LastStateChange: s.lastChange}
+	if s.count == 0 {
+		return snapshot
+	}
+
+	var (
+		up        int
+		total     time.Duration
+		durations []time.Duration
+	)
+	for _, smp := range s.samples[:s.count] {
+		if smp.up {
+			up++
+		}
+		if smp.timed {
+			total += smp.duration
+			durations = append(durations, smp.duration)
+		}
+	}
+
+	snapshot.Uptime = float64(up) / float64(s.count)
+
+	if len(durations) > 0 {
+		slices.Sort(durations)
+		snapshot.AvgResponse = total / time.Duration(len(durations))
+		snapshot.P50Response = percentile(durations, 50)
+		snapshot.P95Response = percentile(durations, 95)
+	}
+
+	return snapshot
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
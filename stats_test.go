@@ -0,0 +1,70 @@
+package gomon
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	start := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	result := func(up bool, ms int, offset time.Duration) *CheckResult {
+		return &CheckResult{
+			Up:    up,
+			Start: start.Add(offset),
+			End:   start.Add(offset + time.Duration(ms)*time.Millisecond),
+		}
+	}
+
+	s := NewStats(4)
+	if got := s.Snapshot(); got.Checks != 0 || got.Uptime != 0 {
+		t.Errorf("empty Snapshot() = %+v", got)
+	}
+
+	// The first result is pushed out of the ring buffer.
+	s.Add(result(false, 1000, 0))
+	s.Add(result(true, 100, time.Minute))
+	s.Add(result(true, 200, 2*time.Minute))
+	s.Add(result(false, 300, 3*time.Minute))
+	s.Add(result(true, 400, 4*time.Minute))
+
+	got := s.Snapshot()
+	if got.Checks != 4 {
+		t.Errorf("Checks = %d, want 4", got.Checks)
+	}
+	if got.Uptime != 0.75 {
+		t.Errorf("Uptime = %v, want 0.75", got.Uptime)
+	}
+	if got.AvgResponse != 250*time.Millisecond {
+		t.Errorf("AvgResponse = %v, want 250ms", got.AvgResponse)
+	}
+	if got.P50Response != 200*time.Millisecond {
+		t.Errorf("P50Response = %v, want 200ms", got.P50Response)
+	}
+	if got.P95Response != 400*time.Millisecond {
+		t.Errorf("P95Response = %v, want 400ms", got.P95Response)
+	}
+	if want := start.Add(4*time.Minute + 400*time.Millisecond); !got.LastStateChange.Equal(want) {
+		t.Errorf("LastStateChange = %v, want %v", got.LastStateChange, want)
+	}
+}
+
+func TestStats_Concurrent(t *testing.T) {
+	s := NewStats(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Add(&CheckResult{Up: true})
+			s.Add(nil)
+			s.Snapshot()
+		}()
+	}
+	wg.Wait()
+
+	if got := s.Snapshot(); got.Checks != 10 {
+		t.Errorf("Checks = %d, want 10", got.Checks)
+	}
+}
@@ -0,0 +1,62 @@
+package gomon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// statusRange is an inclusive range of HTTP status codes parsed from an
+// element of Config.UpStatusRanges.
+type statusRange struct {
+	min, max int
+	exclude  bool // the range was written with a leading "!"
+}
+
+// contains reports whether code is in r.
+func (r statusRange) contains(code int) bool {
+	return code >= r.min && code <= r.max
+}
+
+// parseStatusRange parses a status code range such as "2xx", "200-299",
+// "204" or "!204".
+func parseStatusRange(s string) (statusRange, error) {
+	expr, exclude := strings.CutPrefix(s, "!")
+	r := statusRange{exclude: exclude}
+
+	var err error
+	switch lo, hi, isRange := strings.Cut(expr, "-"); {
+	case isRange:
+		r.min, err = strconv.Atoi(lo)
+		if err == nil {
+			r.max, err = strconv.Atoi(hi)
+		}
+	case len(expr) == 3 && strings.HasSuffix(expr, "xx"):
+		r.min, err = strconv.Atoi(expr[:1])
+		r.min *= 100
+		r.max = r.min + 99
+	default:
+		r.min, err = strconv.Atoi(expr)
+		r.max = r.min
+	}
+
+	if err != nil || r.min < 100 || r.max > 599 || r.min > r.max {
+		return statusRange{}, fmt.Errorf("invalid status range %q", s)
+	}
+
+	return r, nil
+}
+
+// parseStatusRanges parses each of exprs with parseStatusRange.
+func parseStatusRanges(exprs []string) ([]statusRange, error) {
+	ranges := make([]statusRange, 0, len(exprs))
+	for _, expr := range exprs {
+		r, err := parseStatusRange(expr)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
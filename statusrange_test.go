@@ -0,0 +1,87 @@
+package gomon
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseStatusRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    statusRange
+		wantErr bool
+	}{
+		{input: "2xx", want: statusRange{min: 200, max: 299}},
+		{input: "5xx", want: statusRange{min: 500, max: 599}},
+		{input: "200-299", want: statusRange{min: 200, max: 299}},
+		{input: "204", want: statusRange{min: 204, max: 204}},
+		{input: "!204", want: statusRange{min: 204, max: 204, exclude: true}},
+		{input: "!3xx", want: statusRange{min: 300, max: 399, exclude: true}},
+		{input: "6xx", wantErr: true},
+		{input: "xxx", wantErr: true},
+		{input: "299-200", wantErr: true},
+		{input: "200-", wantErr: true},
+		{input: "99", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseStatusRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStatusRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseStatusRange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_isSuccessStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		codes  []int
+		ranges []string
+		up     []int
+		down   []int
+	}{
+		{name: "Default", up: []int{200, 201}, down: []int{202, 204, 301}},
+		{name: "Class", ranges: []string{"2xx"}, up: []int{200, 204, 299}, down: []int{199, 300}},
+		{name: "Class with exclusion", ranges: []string{"2xx", "!204"}, up: []int{200, 206}, down: []int{204}},
+		{name: "Span", ranges: []string{"200-204"}, up: []int{200, 204}, down: []int{205}},
+		{name: "Codes and ranges", codes: []int{304}, ranges: []string{"2xx"}, up: []int{250, 304}, down: []int{301}},
+		{name: "Exclusion overrides codes", codes: []int{200, 204}, ranges: []string{"!204"}, up: []int{200}, down: []int{204}},
+		{name: "Only exclusions", ranges: []string{"!201"}, up: []int{200}, down: []int{201}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            "https://example.com",
+				Method:         http.MethodGet,
+				UpStatusCodes:  tt.codes,
+				UpStatusRanges: tt.ranges,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			for _, code := range tt.up {
+				if !m.isSuccessStatus(code) {
+					t.Errorf("isSuccessStatus(%d) = false, want true", code)
+				}
+			}
+			for _, code := range tt.down {
+				if m.isSuccessStatus(code) {
+					t.Errorf("isSuccessStatus(%d) = true, want false", code)
+				}
+			}
+		})
+	}
+
+	_, err := NewMonitor(Config{URL: "https://example.com", Method: http.MethodGet, UpStatusRanges: []string{"2xx", "20x"}})
+	if err == nil {
+		t.Errorf("NewMonitor() error = nil, want error for invalid range")
+	}
+}
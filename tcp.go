@@ -0,0 +1,65 @@
+package gomon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// tcpScheme is the URL scheme for checks that only open a TCP connection,
+// such as tcp://mail.example.com:25.
+const tcpScheme = "tcp"
+
+// isTCPURL reports whether rawURL uses the TCP check scheme.
+func isTCPURL(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	return err == nil && parsedURL.Scheme == tcpScheme
+}
+
+// validateTCPURL checks that a TCP check URL includes a port.
+func validateTCPURL(rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if parsedURL.Port() == "" {
+		return fmt.Errorf("missing port %q", rawURL)
+	}
+
+	return nil
+}
+
+// checkTCP opens and closes a TCP connection to the configured host and
+// port. The site is up if the connection succeeds.
+func (m *Monitor) checkTCP(ctx context.Context) (*CheckResult, error) {
+	result := CheckResult{URL: m.config.URL}
+
+	parsedURL, err := url.Parse(m.config.URL)
+	if err != nil {
+		result.Start = time.Now()
+		result.End = result.Start
+		return result.failed(&CheckError{Phase: PhaseRequest, URL: m.config.URL, Err: err})
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, m.config.RequestTimeout)
+	defer cancel()
+
+	trace := &tracer{}
+	result.Start = time.Now()
+	conn, err := m.dial(trace.withTrace(dialCtx), "tcp", parsedURL.Host)
+	result.End = time.Now()
+	result.ResolvedAddrs = trace.resolved()
+
+	if err != nil {
+		return result.failed(&CheckError{Phase: sendPhase(err), URL: m.config.URL, Err: classifyContextErr(ctx, err)})
+	}
+	result.RemoteAddr = conn.RemoteAddr().String()
+	conn.Close()
+
+	result.Up = true
+	result.Timings.Connect = result.End.Sub(result.Start)
+
+	return &result, nil
+}
@@ -0,0 +1,68 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestMonitor_CheckTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	openAddr := ln.Addr().String()
+	defer ln.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		wantErr   bool
+		wantPhase Phase
+	}{
+		{name: "Open port", url: "tcp://" + openAddr},
+		{name: "Closed port", url: "tcp://" + closedAddr, wantErr: true, wantPhase: PhaseConnect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: tt.url})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr {
+				var checkErr *CheckError
+				if !errors.As(err, &checkErr) || checkErr.Phase != tt.wantPhase {
+					t.Errorf("Check() error = %v, want phase %v", err, tt.wantPhase)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.IsUp() {
+				t.Errorf("IsUp() = false, want true")
+			}
+			if got.Timings.Connect <= 0 {
+				t.Errorf("Timings.Connect = %v, want > 0", got.Timings.Connect)
+			}
+		})
+	}
+}
+
+func TestNewMonitor_TCPMissingPort(t *testing.T) {
+	if _, err := NewMonitor(Config{URL: "tcp://localhost"}); err == nil {
+		t.Errorf("NewMonitor() error = nil, want missing port error")
+	}
+}
@@ -0,0 +1,79 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSlowTransfer indicates the response body arrived more slowly than
+// Config.MinThroughput.
+var ErrSlowTransfer = errors.New("transfer below minimum throughput")
+
+// throughputWindow is how long the body is read before its throughput is
+// first compared with MinThroughput, and how often after that, so that a
+// slow start or a brief pause is not mistaken for a slow transfer.
+const throughputWindow = time.Second
+
+// meter counts the bytes read from a response body, as received and before
+// any decompression.
+type meter struct {
+	io.ReadCloser
+	start time.Time
+	n     atomic.Int64
+}
+
+// newMeter returns a meter for body, starting its clock.
+func newMeter(body io.ReadCloser) *meter {
+	return &meter{ReadCloser: body, start: time.Now()}
+}
+
+// Read implements io.Reader.
+func (mr *meter) Read(p []byte) (int, error) {
+	n, err := mr.ReadCloser.Read(p)
+	mr.n.Add(int64(n))
+	return n, err
+}
+
+// throughput returns the bytes per second read since the meter started.
+func (mr *meter) throughput() float64 {
+	elapsed := time.Since(mr.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(mr.n.Load()) / elapsed
+}
+
+// watchThroughput cancels the request with ErrSlowTransfer if the body
+// read through mr arrives at fewer than minRate bytes per second, checked
+// every throughputWindow. It returns a function that ends the watch. A
+// minRate of zero watches nothing.
+func watchThroughput(mr *meter, minRate int64, cancel context.CancelCauseFunc) (stop func()) {
+	if minRate <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(throughputWindow)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if rate := mr.throughput(); rate < float64(minRate) {
+					cancel(fmt.Errorf("%w: %.0f bytes/s, want at least %d", ErrSlowTransfer, rate, minRate))
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
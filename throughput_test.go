@@ -0,0 +1,80 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckMinThroughput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fast" {
+			w.Write([]byte(strings.Repeat("x", 64<<10)))
+			return
+		}
+
+		// Trickle the body a byte at a time until the client gives up.
+		for {
+			w.Write([]byte("x"))
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "Fast body", path: "/fast"},
+		{name: "Trickled body", path: "/slow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            ts.URL + tt.path,
+				Method:         http.MethodGet,
+				RequestTimeout: 10 * time.Second,
+				MinThroughput:  1000,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			start := time.Now()
+			got, err := m.Check(context.Background())
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Check() took %s, want the slow transfer detected early", elapsed)
+			}
+			if got.Throughput <= 0 {
+				t.Errorf("Throughput = %v, want > 0", got.Throughput)
+			}
+
+			if !tt.wantErr {
+				if err != nil || !got.IsUp() {
+					t.Errorf("Check() = %v, %v, want up", got.Summary(), err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrSlowTransfer) {
+				t.Fatalf("Check() error = %v, want %v", err, ErrSlowTransfer)
+			}
+			if got.ErrorCode != FailureTimeout {
+				t.Errorf("ErrorCode = %q, want %q", got.ErrorCode, FailureTimeout)
+			}
+			if got.Throughput >= 1000 {
+				t.Errorf("Throughput = %v, want < 1000", got.Throughput)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gomon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// newTLSConfig creates the client TLS configuration described by config.
+func newTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.IgnoreCert,
+		RootCAs:            config.RootCAs,
+	}
+
+	if config.ClientCertFile != "" || config.ClientKeyFile != "" {
+		if config.ClientCertFile == "" || config.ClientKeyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both ClientCertFile and ClientKeyFile")
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.ClientCertFile, config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// dialTLSFunc returns a function for http.Transport.DialTLSContext that
+// opens connections with dial and sends serverName for SNI, and verifies
+// it, only on connections to urlHost, leaving other hosts, such as those
+// reached by redirects, to their own names. The TLS configuration is read
+// from transport on each dial, after HTTP/2 adds its protocols. The
+// transport performs the handshake, within handshakeTimeout if positive.
+func dialTLSFunc(dial dialFunc, transport *http.Transport, handshakeTimeout time.Duration, urlHost, serverName string) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig := transport.TLSClientConfig.Clone()
+		tlsConfig.ServerName = host
+		if strings.EqualFold(host, urlHost) {
+			tlsConfig.ServerName = serverName
+		}
+
+		if handshakeTimeout > 0 {
+			// The deadline is cleared once the certificate is accepted;
+			// a handshake that fails closes the connection.
+			conn.SetDeadline(time.Now().Add(handshakeTimeout))
+			tlsConfig.VerifyConnection = func(tls.ConnectionState) error {
+				return conn.SetDeadline(time.Time{})
+			}
+		}
+
+		return tls.Client(conn, tlsConfig), nil
+	}
+}
+
+// loadRootCAs returns a copy of base, or an empty pool if base is nil, with
+// the PEM certificates in file added.
+func loadRootCAs(base *x509.CertPool, file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root CA file: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	if base != nil {
+		roots = base.Clone()
+	}
+
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in root CA file %q", file)
+	}
+
+	return roots, nil
+}
+
+// hostname returns host without any port.
+func hostname(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+
+	return host
+}
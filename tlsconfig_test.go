@@ -0,0 +1,365 @@
+package gomon
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor_CheckClientCert(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	clientCert, clientKey := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "gomon client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+	certFile, keyFile := writePEM(t, clientCert, clientKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		wantErr  bool
+	}{
+		{name: "With client certificate", certFile: certFile, keyFile: keyFile},
+		{name: "Without client certificate", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            ts.URL,
+				Method:         http.MethodGet,
+				IgnoreCert:     true,
+				ClientCertFile: tt.certFile,
+				ClientKeyFile:  tt.keyFile,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.IsUp() {
+				t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig_ClientCertErrors(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	certFile, _ := writePEM(t, ca, caKey)
+	other, otherKey := newTestCA(t)
+	_, otherKeyFile := writePEM(t, other, otherKey)
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+	}{
+		{name: "Missing key", certFile: certFile},
+		{name: "Mismatched key", certFile: certFile, keyFile: otherKeyFile},
+		{name: "Nonexistent file", certFile: certFile, keyFile: certFile + ".missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMonitor(Config{
+				URL:            "https://example.com",
+				Method:         http.MethodGet,
+				ClientCertFile: tt.certFile,
+				ClientKeyFile:  tt.keyFile,
+			})
+			if err == nil {
+				t.Errorf("NewMonitor() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckRootCAs(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	caFile, _ := writePEM(t, ts.Certificate(), ts.TLS.Certificates[0].PrivateKey.(crypto.Signer))
+
+	tests := []struct {
+		name       string
+		rootCAs    *x509.CertPool
+		rootCAFile string
+		wantErr    bool
+	}{
+		{name: "System roots", wantErr: true},
+		{name: "RootCAs", rootCAs: pool},
+		{name: "RootCAFile", rootCAFile: caFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:        ts.URL,
+				Method:     http.MethodGet,
+				RootCAs:    tt.rootCAs,
+				RootCAFile: tt.rootCAFile,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.CertInfo.IsValid {
+				t.Errorf("CertInfo.IsValid = false, ErrorMsg %q", got.CertInfo.ErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckCertVerifyHost(t *testing.T) {
+	// The test certificate is issued for example.com and 127.0.0.1, but
+	// not localhost.
+	var localhostURL string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, localhostURL, http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+	localhostURL = strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	tests := []struct {
+		name           string
+		url            string
+		certVerifyHost string
+		wantValid      bool
+	}{
+		{name: "Redirect target verified", url: ts.URL + "/redirect", wantValid: false},
+		{name: "Redirect with CertVerifyHost", url: ts.URL + "/redirect", certVerifyHost: "example.com", wantValid: true},
+		{name: "Host override", url: localhostURL, wantValid: false},
+		{name: "Host override with CertVerifyHost", url: localhostURL, certVerifyHost: "example.com", wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:            tt.url,
+				Method:         http.MethodGet,
+				IgnoreCert:     true, // the handshake would reject localhost
+				RootCAs:        pool,
+				CertVerifyHost: tt.certVerifyHost,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.CertInfo.IsValid != tt.wantValid {
+				t.Errorf("CertInfo.IsValid = %v, want %v (ErrorMsg %q)", got.CertInfo.IsValid, tt.wantValid, got.CertInfo.ErrorMsg)
+			}
+		})
+	}
+}
+
+func TestLoadRootCAs_Errors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for _, file := range []string{empty, empty + ".missing"} {
+		if _, err := loadRootCAs(nil, file); err == nil {
+			t.Errorf("loadRootCAs(%q) error = nil, want error", file)
+		}
+	}
+}
+
+func TestMonitor_CheckHostOverride(t *testing.T) {
+	// The test certificate is issued for example.com and 127.0.0.1.
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("host=" + r.Host + " sni=" + r.TLS.ServerName))
+	}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	port := ts.Listener.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name         string
+		hostOverride string
+		wantBody     string
+		wantErr      bool
+	}{
+		{name: "Virtual host", hostOverride: "example.com", wantBody: "host=example.com sni=example.com"},
+		{name: "Virtual host with port", hostOverride: fmt.Sprintf("example.com:%d", port), wantBody: fmt.Sprintf("host=example.com:%d sni=example.com", port)},
+		{name: "Certificate mismatch", hostOverride: "vhost.test", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:          ts.URL,
+				Method:       http.MethodGet,
+				RootCAs:      pool,
+				HostOverride: tt.hostOverride,
+				BodyContains: tt.wantBody,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if tt.wantErr {
+				var checkErr *CheckError
+				if !errors.As(err, &checkErr) || checkErr.Phase != PhaseTLS {
+					t.Errorf("Check() error = %v, want TLS error", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !got.BodyMatched {
+				t.Errorf("server did not receive %q", tt.wantBody)
+			}
+			if !got.CertInfo.IsValid {
+				t.Errorf("CertInfo.IsValid = false, ErrorMsg %q", got.CertInfo.ErrorMsg)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckHostOverrideRedirect(t *testing.T) {
+	// The redirect target is another host, reached by IP address, for
+	// which no SNI is sent, so the override must not follow the redirect.
+	other := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS.ServerName != "" {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+		}
+	}))
+	defer other.Close()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS.ServerName != "vhost.example.com" {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	port := ts.Listener.Addr().(*net.TCPAddr).Port
+
+	m, err := NewMonitor(Config{
+		URL:          fmt.Sprintf("https://example.com:%d", port),
+		Method:       http.MethodGet,
+		RootCAs:      pool,
+		DialIP:       "127.0.0.1",
+		HostOverride: "vhost.example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	got, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !got.IsUp() {
+		t.Errorf("IsUp() = false, reasons %q", got.Reasons())
+	}
+	if got.RedirectCount != 1 {
+		t.Errorf("RedirectCount = %d, want 1", got.RedirectCount)
+	}
+	if !got.CertInfo.IsValid {
+		t.Errorf("CertInfo.IsValid = false, ErrorMsg %q", got.CertInfo.ErrorMsg)
+	}
+}
+
+func TestMonitor_CheckExpectedIssuers(t *testing.T) {
+	// The test certificate is self-signed, with issuer "O=Acme Co".
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	tests := []struct {
+		name    string
+		url     string
+		issuers []string
+		wantUp  bool
+	}{
+		{name: "Not checked", url: ts.URL, wantUp: true},
+		{name: "Exact", url: ts.URL, issuers: []string{"O=Acme Co"}, wantUp: true},
+		{name: "Substring", url: ts.URL, issuers: []string{"O=Let's Encrypt", "Acme"}, wantUp: true},
+		{name: "Unexpected issuer", url: ts.URL, issuers: []string{"O=Let's Encrypt", "O=DigiCert Inc"}},
+		{name: "Without TLS", url: plain.URL, issuers: []string{"O=Acme Co"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:             tt.url,
+				Method:          http.MethodGet,
+				RootCAs:         pool,
+				ExpectedIssuers: tt.issuers,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if got.IsUp() != tt.wantUp {
+				t.Errorf("IsUp() = %v, want %v (reasons %q)", got.IsUp(), tt.wantUp, got.Reasons())
+			}
+		})
+	}
+}
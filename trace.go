@@ -0,0 +1,123 @@
+package gomon
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings records the duration of each phase of a check. A phase that did
+// not occur, such as the TLS handshake for plain HTTP or DNS for a reused
+// connection, has a zero duration. Phases repeated for redirects are summed.
+type Timings struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration // from request start to the final response
+}
+
+// tracer collects Timings and the connection address from httptrace
+// callbacks.
+type tracer struct {
+	mu      sync.Mutex
+	start   time.Time
+	timings Timings
+	addr    string   // remote address of the last connection
+	addrs   []string // addresses from the last DNS lookup
+	reused  bool     // whether the last connection was reused
+
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	tlsErr       error // error from the last TLS handshake
+}
+
+// withTrace returns a context that records timings into t.
+func (t *tracer) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.DNS += time.Since(t.dnsStart)
+			t.addrs = nil
+			for _, addr := range info.Addrs {
+				t.addrs = append(t.addrs, addr.String())
+			}
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.Connect += time.Since(t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.TLSHandshake += time.Since(t.tlsStart)
+			t.tlsErr = err
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.addr = info.Conn.RemoteAddr().String()
+			t.reused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.FirstByte = time.Since(t.start)
+		},
+	})
+}
+
+// result returns the collected timings.
+func (t *tracer) result() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timings
+}
+
+// conn returns the remote address of the last connection used and whether
+// it was reused from an earlier request.
+func (t *tracer) conn() (addr string, reused bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.addr, t.reused
+}
+
+// resolved returns the addresses from the last DNS lookup.
+func (t *tracer) resolved() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.addrs
+}
+
+// handshakeTimedOut reports whether the last TLS handshake failed because
+// it exceeded the transport's TLSHandshakeTimeout, rather than because the
+// request was cancelled or timed out.
+func (t *tracer) handshakeTimedOut() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var netErr net.Error
+	return errors.As(t.tlsErr, &netErr) && netErr.Timeout() &&
+		!errors.Is(t.tlsErr, context.DeadlineExceeded) && !errors.Is(t.tlsErr, context.Canceled)
+}
@@ -0,0 +1,125 @@
+package gomon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMonitor_CheckTimings(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantTLS bool
+	}{
+		{name: "HTTP", url: httpServer.URL, wantTLS: false},
+		{name: "HTTPS", url: tlsServer.URL, wantTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{URL: tt.url, Method: http.MethodGet, IgnoreCert: true})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			got, err := m.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			timings := got.Timings
+			if timings.Connect <= 0 {
+				t.Errorf("Connect = %v, want > 0", timings.Connect)
+			}
+			if timings.FirstByte <= 0 {
+				t.Errorf("FirstByte = %v, want > 0", timings.FirstByte)
+			}
+			if (timings.TLSHandshake > 0) != tt.wantTLS {
+				t.Errorf("TLSHandshake = %v, wantTLS %v", timings.TLSHandshake, tt.wantTLS)
+			}
+		})
+	}
+}
+
+func TestMonitor_CheckConnectionReused(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	// A short body is read to the end even without ReadBody, which lets
+	// the connection return to the pool.
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	for i, want := range []bool{false, true} {
+		got, err := m.Check(context.Background())
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if got.ConnectionReused != want {
+			t.Errorf("check %d: ConnectionReused = %v, want %v", i+1, got.ConnectionReused, want)
+		}
+
+		line := "Remote Address: " + ts.Listener.Addr().String()
+		if want {
+			line += " (reused connection)"
+		}
+		if got.RemoteAddr != ts.Listener.Addr().String() || !strings.Contains(got.String(), line+"\n") {
+			t.Errorf("check %d: String() does not include %q:\n%s", i+1, line, got.String())
+		}
+	}
+}
+
+func TestMonitor_CheckDisableKeepAlives(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		client *http.Client
+	}{
+		{name: "Default client"},
+		{name: "Custom client", client: &http.Client{Transport: &http.Transport{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMonitor(Config{
+				URL:               ts.URL,
+				Method:            http.MethodGet,
+				ReadBody:          true,
+				DisableKeepAlives: true,
+				HTTPClient:        tt.client,
+			})
+			if err != nil {
+				t.Fatalf("NewMonitor() error = %v", err)
+			}
+
+			for i := 1; i <= 2; i++ {
+				got, err := m.Check(context.Background())
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				if got.ConnectionReused {
+					t.Errorf("check %d: ConnectionReused = true, want false", i)
+				}
+			}
+		})
+	}
+}
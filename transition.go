@@ -0,0 +1,78 @@
+package gomon
+
+import (
+	"fmt"
+	"time"
+)
+
+// Transition describes how a check result differs from the previous one.
+type Transition int
+
+const (
+	TransitionNone             Transition = iota // no significant change
+	TransitionDown                               // the site went down
+	TransitionUp                                 // the site came back up
+	TransitionCertInvalid                        // the certificate became invalid
+	TransitionCertValid                          // the certificate became valid again
+	TransitionCertExpiringSoon                   // the certificate crossed the expiry threshold
+)
+
+// String returns the name of the transition.
+func (t Transition) String() string {
+	switch t {
+	case TransitionNone:
+		return "None"
+	case TransitionDown:
+		return "Down"
+	case TransitionUp:
+		return "Up"
+	case TransitionCertInvalid:
+		return "CertInvalid"
+	case TransitionCertValid:
+		return "CertValid"
+	case TransitionCertExpiringSoon:
+		return "CertExpiringSoon"
+	default:
+		return fmt.Sprintf("Transition(%d)", int(t))
+	}
+}
+
+// Transition compares result with prev, the result of the previous check,
+// and returns the most significant change. A change between up and down
+// takes precedence over a change in certificate validity, which takes
+// precedence over the certificate coming within expiryThreshold of
+// expiring, measured from the end of each check. Certificate changes are
+// only reported when both results have certificate details. A nil prev,
+// as for the first check, is never a transition, and either result may be
+// nil for a failed check.
+func (result *CheckResult) Transition(prev *CheckResult, expiryThreshold time.Duration) Transition {
+	if prev == nil {
+		return TransitionNone
+	}
+
+	switch {
+	case prev.IsUp() && !result.IsUp():
+		return TransitionDown
+	case !prev.IsUp() && result.IsUp():
+		return TransitionUp
+	}
+
+	if result == nil || prev.CertInfo == nil || result.CertInfo == nil {
+		return TransitionNone
+	}
+
+	switch {
+	case prev.CertInfo.IsValid && !result.CertInfo.IsValid:
+		return TransitionCertInvalid
+	case !prev.CertInfo.IsValid && result.CertInfo.IsValid:
+		return TransitionCertValid
+	}
+
+	if expiryThreshold > 0 &&
+		prev.CertInfo.ValidTo.Sub(prev.End) >= expiryThreshold &&
+		result.CertInfo.ValidTo.Sub(result.End) < expiryThreshold {
+		return TransitionCertExpiringSoon
+	}
+
+	return TransitionNone
+}
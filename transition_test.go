@@ -0,0 +1,56 @@
+package gomon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckResult_Transition(t *testing.T) {
+	now := time.Now()
+	week := 7 * 24 * time.Hour
+
+	withCert := func(up, valid bool, expiresIn time.Duration) *CheckResult {
+		return &CheckResult{
+			Up:       up,
+			End:      now,
+			CertInfo: &CertInfo{IsValid: valid, ValidTo: now.Add(expiresIn)},
+		}
+	}
+
+	tests := []struct {
+		name string
+		prev *CheckResult
+		curr *CheckResult
+		want Transition
+	}{
+		{name: "First check", prev: nil, curr: &CheckResult{Up: true}, want: TransitionNone},
+		{name: "Still up", prev: &CheckResult{Up: true}, curr: &CheckResult{Up: true}, want: TransitionNone},
+		{name: "Went down", prev: &CheckResult{Up: true}, curr: &CheckResult{Up: false}, want: TransitionDown},
+		{name: "Failed check", prev: &CheckResult{Up: true}, curr: nil, want: TransitionDown},
+		{name: "Came up after failure", prev: &CheckResult{}, curr: &CheckResult{Up: true}, want: TransitionUp},
+		{name: "Down takes precedence", prev: withCert(true, true, 4*week), curr: withCert(false, false, 4*week), want: TransitionDown},
+		{name: "Cert invalid", prev: withCert(true, true, 4*week), curr: withCert(true, false, 4*week), want: TransitionCertInvalid},
+		{name: "Cert valid", prev: withCert(true, false, 4*week), curr: withCert(true, true, 4*week), want: TransitionCertValid},
+		{name: "Cert expiring soon", prev: withCert(true, true, 2*week), curr: withCert(true, true, week-time.Hour), want: TransitionCertExpiringSoon},
+		{name: "Cert still expiring", prev: withCert(true, true, week-time.Hour), curr: withCert(true, true, week-2*time.Hour), want: TransitionNone},
+		{name: "Cert renewed", prev: withCert(true, true, week-time.Hour), curr: withCert(true, true, 12*week), want: TransitionNone},
+		{name: "No cert", prev: &CheckResult{Up: true}, curr: withCert(true, false, 4*week), want: TransitionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curr.Transition(tt.prev, week); got != tt.want {
+				t.Errorf("Transition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransition_String(t *testing.T) {
+	if got := TransitionCertExpiringSoon.String(); got != "CertExpiringSoon" {
+		t.Errorf("String() = %q, want %q", got, "CertExpiringSoon")
+	}
+	if got := Transition(99).String(); got != "Transition(99)" {
+		t.Errorf("String() = %q, want %q", got, "Transition(99)")
+	}
+}
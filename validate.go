@@ -0,0 +1,98 @@
+package gomon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ValidateConfig reports problems with config without sending any
+// requests. It applies the checks made by NewMonitor, including loading
+// certificate files, and those made by Monitor.Validate.
+func ValidateConfig(config Config) error {
+	m, err := NewMonitor(config)
+	if err != nil {
+		return err
+	}
+
+	return m.Validate()
+}
+
+// Validate reports settings that are accepted by NewMonitor but are
+// inconsistent or have no effect. All problems are returned, joined with
+// errors.Join.
+func (m *Monitor) Validate() error {
+	var errs []error
+	config := m.config
+
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if m.tcp {
+		if config.BodyContains != "" || config.MinBodyBytes > 0 || config.MinThroughput > 0 ||
+			len(config.ExpectedHeaders) > 0 || len(config.ExpectedTrailers) > 0 || len(config.SecurityHeaders) > 0 ||
+			config.SuccessFunc != nil || config.OnResponse != nil {
+			invalid("response expectations do not apply to a TCP check")
+		}
+	}
+
+	if config.BodyContains != "" && config.Method == http.MethodHead {
+		invalid("BodyContains requires a response body, but HEAD responses have none")
+	}
+
+	if config.MinBodyBytes > 0 && config.Method == http.MethodHead {
+		invalid("MinBodyBytes requires a response body, but HEAD responses have none")
+	}
+
+	if config.MinThroughput > 0 && config.Method == http.MethodHead {
+		invalid("MinThroughput requires a response body, but HEAD responses have none")
+	}
+
+	if config.MinTLSVersion != 0 && strings.HasPrefix(config.URL, "http://") {
+		invalid("MinTLSVersion requires HTTPS, but URL %q uses HTTP", config.URL)
+	}
+
+	if config.ExpectedCertFingerprint != "" && strings.HasPrefix(config.URL, "http://") {
+		invalid("ExpectedCertFingerprint requires HTTPS, but URL %q uses HTTP", config.URL)
+	}
+
+	if len(config.ExpectedIssuers) > 0 && strings.HasPrefix(config.URL, "http://") {
+		invalid("ExpectedIssuers requires HTTPS, but URL %q uses HTTP", config.URL)
+	}
+
+	if config.ExpectHTTP2 && strings.HasPrefix(config.URL, "http://") {
+		invalid("ExpectHTTP2 requires HTTPS, but URL %q uses HTTP", config.URL)
+	}
+
+	if config.ExpectedRedirectLocation != "" && !config.DontFollowRedirect {
+		invalid("ExpectedRedirectLocation has no effect without DontFollowRedirect")
+	}
+
+	if config.IgnoreCert && config.RootCAs != nil {
+		invalid("RootCAs has no effect when IgnoreCert is set")
+	}
+
+	if config.RetryDownStatus && config.RetryCount == 0 {
+		invalid("RetryDownStatus has no effect when RetryCount is 0")
+	}
+
+	if m.retry.MaxAttempts > 1 && !m.tcp && !config.AllowUnsafeRetries &&
+		!slices.Contains(retryableMethods, config.Method) {
+		invalid("retries have no effect for %s without AllowUnsafeRetries", config.Method)
+	}
+
+	if config.ResponseTimeThreshold >= config.RequestTimeout {
+		invalid("ResponseTimeThreshold %s is not less than RequestTimeout %s",
+			config.ResponseTimeThreshold, config.RequestTimeout)
+	}
+
+	if config.TotalTimeout > 0 && config.TotalTimeout < config.RequestTimeout {
+		invalid("TotalTimeout %s is less than RequestTimeout %s",
+			config.TotalTimeout, config.RequestTimeout)
+	}
+
+	return errors.Join(errs...)
+}
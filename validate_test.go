@@ -0,0 +1,109 @@
+package gomon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		wantErrors int
+	}{
+		{
+			name:   "Valid",
+			config: Config{URL: "https://example.com", Method: http.MethodGet, BodyContains: "ok"},
+		},
+		{
+			name:       "Rejected by NewMonitor",
+			config:     Config{URL: "example.com", Method: http.MethodGet},
+			wantErrors: 1,
+		},
+		{
+			name:       "Missing client key",
+			config:     Config{URL: "https://example.com", Method: http.MethodGet, ClientCertFile: "client.pem"},
+			wantErrors: 1,
+		},
+		{
+			name: "Redirect location while following redirects",
+			config: Config{
+				URL:                      "http://example.com",
+				Method:                   http.MethodGet,
+				ExpectedRedirectLocation: "https://example.com/",
+			},
+			wantErrors: 1,
+		},
+		{
+			name:       "Expected issuers over HTTP",
+			config:     Config{URL: "http://example.com", Method: http.MethodGet, ExpectedIssuers: []string{"O=Acme Co"}},
+			wantErrors: 1,
+		},
+		{
+			name:       "HEAD with BodyContains",
+			config:     Config{URL: "https://example.com", Method: http.MethodHead, BodyContains: "ok"},
+			wantErrors: 1,
+		},
+		{
+			name: "Pinned certificate over HTTP",
+			config: Config{
+				URL:                     "http://example.com",
+				Method:                  http.MethodGet,
+				ExpectedCertFingerprint: "0000000000000000000000000000000000000000000000000000000000000000",
+			},
+			wantErrors: 1,
+		},
+		{
+			name: "Several problems",
+			config: Config{
+				URL:                   "http://example.com",
+				Method:                http.MethodGet,
+				MinTLSVersion:         tls.VersionTLS12,
+				IgnoreCert:            true,
+				RootCAs:               x509.NewCertPool(),
+				RetryDownStatus:       true,
+				ResponseTimeThreshold: time.Minute,
+			},
+			wantErrors: 4,
+		},
+		{
+			name:       "Retry of unsafe method",
+			config:     Config{URL: "https://example.com", Method: http.MethodPost, RetryCount: 2},
+			wantErrors: 1,
+		},
+		{
+			name: "Retry of unsafe method allowed",
+			config: Config{
+				URL:                "https://example.com",
+				Method:             http.MethodPost,
+				RetryCount:         2,
+				AllowUnsafeRetries: true,
+			},
+		},
+		{
+			name:       "TCP with body expectation",
+			config:     Config{URL: "tcp://example.com:25", BodyContains: "ok"},
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfig(tt.config)
+
+			got := 0
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				got = len(joined.Unwrap())
+			} else if err != nil {
+				got = 1
+			}
+
+			if got != tt.wantErrors {
+				t.Errorf("ValidateConfig() = %v, want %d errors", err, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WaitResult describes a call to WaitUntilHealthy.
+type WaitResult struct {
+	Result *CheckResult  // the last check
+	Checks int           // number of checks run
+	Waited time.Duration // time from the call until the last check ended
+}
+
+// WaitUntilHealthy runs Check until the site is healthy, waiting
+// pollInterval, varied by Config.Jitter, between checks. Each check makes
+// the attempts allowed by the retry settings. It returns nil once a check
+// is healthy. If ctx is done first, it returns an error wrapping the
+// context's error and describing the last failure. The WaitResult is
+// never nil.
+func (m *Monitor) WaitUntilHealthy(ctx context.Context, pollInterval time.Duration) (*WaitResult, error) {
+	start := time.Now()
+	wait := &WaitResult{}
+
+	if pollInterval <= 0 {
+		return wait, fmt.Errorf("poll interval %s is not positive", pollInterval)
+	}
+
+	for {
+		result, err := m.Check(ctx)
+		wait.Result = result
+		wait.Checks++
+		wait.Waited = time.Since(start)
+
+		if err == nil && result.Healthy() {
+			m.logger().DebugContext(ctx, "healthy", "checks", wait.Checks, "waited", wait.Waited)
+			return wait, nil
+		}
+
+		last := err
+		if last == nil {
+			last = errors.New(strings.Join(result.Reasons(), "; "))
+		}
+
+		if !sleep(ctx, jittered(pollInterval, m.config.Jitter)) {
+			return wait, fmt.Errorf("%w: not healthy after %s and %d checks: %w",
+				context.Cause(ctx), wait.Waited.Round(time.Millisecond), wait.Checks, last)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gomon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor_WaitUntilHealthy(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wait, err := m.WaitUntilHealthy(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitUntilHealthy() error = %v", err)
+	}
+	if wait.Checks != 3 {
+		t.Errorf("Checks = %d, want 3", wait.Checks)
+	}
+	if !wait.Result.Healthy() {
+		t.Errorf("Result not healthy: %v", wait.Result.Reasons())
+	}
+	if wait.Waited < 20*time.Millisecond {
+		t.Errorf("Waited = %v, want at least two poll intervals", wait.Waited)
+	}
+}
+
+func TestMonitor_WaitUntilHealthyTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(Config{URL: ts.URL, Method: http.MethodGet})
+	if err != nil {
+		t.Fatalf("NewMonitor() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	wait, err := m.WaitUntilHealthy(ctx, 20*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitUntilHealthy() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !strings.Contains(err.Error(), "unexpected status code 503") {
+		t.Errorf("WaitUntilHealthy() error = %v, want last failure", err)
+	}
+	if wait.Checks < 2 || wait.Result == nil || wait.Result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("WaitResult = %+v, want several checks ending with 503", wait)
+	}
+
+	if _, err := m.WaitUntilHealthy(context.Background(), 0); err == nil {
+		t.Errorf("WaitUntilHealthy() with zero interval error = nil, want error")
+	}
+}